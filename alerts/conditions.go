@@ -0,0 +1,155 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+)
+
+// violation is an object for which the condition of a rule is true
+type violation struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Message   string
+}
+
+// check returns the objects that violate the given rule
+func (e *Engine) check(rule Rule, data *api.Data, now time.Time) []violation {
+	switch rule.Type {
+	case RuleTypePodRestarts:
+		return e.checkPodRestarts(rule, data, now)
+	case RuleTypeBuildFailureRate:
+		return checkBuildFailureRate(rule, data, now)
+	case RuleTypeUnavailableReplicas:
+		return checkUnavailableReplicas(rule, data)
+	case RuleTypeRouteWithoutEndpoints:
+		return checkRouteWithoutEndpoints(rule, data)
+	}
+	return nil
+}
+
+// checkPodRestarts returns the pods that restarted more than the threshold during the window
+func (e *Engine) checkPodRestarts(rule Rule, data *api.Data, now time.Time) []violation {
+	violations := []violation{}
+	for _, pod := range data.Pods {
+		if !rule.appliesTo(pod.Namespace) {
+			continue
+		}
+
+		restarts := e.restarts.increase(pod, time.Duration(rule.Window), now)
+		if float64(restarts) > rule.Threshold {
+			violations = append(violations, violation{
+				Namespace: pod.Namespace,
+				Kind:      "Pod",
+				Name:      pod.Name,
+				Message:   fmt.Sprintf("%d restarts in the last %v", restarts, rule.Window),
+			})
+		}
+	}
+	return violations
+}
+
+// checkBuildFailureRate returns the BuildConfigs with a failure rate above the threshold,
+// for the builds completed during the window
+func checkBuildFailureRate(rule Rule, data *api.Data, now time.Time) []violation {
+	since := now.Add(-time.Duration(rule.Window))
+
+	violations := []violation{}
+	for _, bc := range data.BuildConfigs {
+		if !rule.appliesTo(bc.Namespace) {
+			continue
+		}
+
+		total, failed := 0, 0
+		for _, build := range data.BuildsOf(bc) {
+			if build.Status.CompletionTimestamp == nil || build.Status.CompletionTimestamp.Time.Before(since) {
+				continue
+			}
+			switch build.Status.Phase {
+			case buildapi.BuildPhaseComplete:
+				total++
+			case buildapi.BuildPhaseFailed, buildapi.BuildPhaseError:
+				total++
+				failed++
+			}
+		}
+		if total == 0 {
+			continue
+		}
+
+		rate := float64(failed) * 100 / float64(total)
+		if rate > rule.Threshold {
+			violations = append(violations, violation{
+				Namespace: bc.Namespace,
+				Kind:      "BuildConfig",
+				Name:      bc.Name,
+				Message:   fmt.Sprintf("%d of %d builds failed in the last %v (%.0f%%)", failed, total, rule.Window, rate),
+			})
+		}
+	}
+	return violations
+}
+
+// checkUnavailableReplicas returns the DeploymentConfigs whose latest deployment
+// has less ready pods than desired replicas
+func checkUnavailableReplicas(rule Rule, data *api.Data) []violation {
+	violations := []violation{}
+	for _, dc := range data.DeploymentConfigs {
+		if !rule.appliesTo(dc.Namespace) {
+			continue
+		}
+
+		rc := data.LatestDeploymentOf(dc)
+		if rc == nil {
+			continue
+		}
+
+		available := 0
+		for _, pod := range data.PodsOfDeployment(*rc) {
+			if api.IsPodReady(pod) {
+				available++
+			}
+		}
+
+		if available < rc.Spec.Replicas {
+			violations = append(violations, violation{
+				Namespace: dc.Namespace,
+				Kind:      "DeploymentConfig",
+				Name:      dc.Name,
+				Message:   fmt.Sprintf("%d of %d replicas available for deployment %v", available, rc.Spec.Replicas, rc.Name),
+			})
+		}
+	}
+	return violations
+}
+
+// checkRouteWithoutEndpoints returns the routes that point to a service without endpoints,
+// or none if the endpoints have not been loaded
+func checkRouteWithoutEndpoints(rule Rule, data *api.Data) []violation {
+	violations := []violation{}
+	if data.Endpoints == nil {
+		return violations
+	}
+	for _, route := range data.Routes {
+		if !rule.appliesTo(route.Namespace) {
+			continue
+		}
+
+		endpoints, found := data.FindEndpoints(route.Namespace, route.ServiceName)
+		if found && api.AddressesCountOf(*endpoints) > 0 {
+			continue
+		}
+
+		violations = append(violations, violation{
+			Namespace: route.Namespace,
+			Kind:      "Route",
+			Name:      route.Name,
+			Message:   fmt.Sprintf("service %v has no endpoints for host %v", route.ServiceName, route.Host),
+		})
+	}
+	return violations
+}
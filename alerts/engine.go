@@ -0,0 +1,228 @@
+package alerts
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+)
+
+const (
+	// resolvedRetention is how long the resolved alerts are kept, before being forgotten
+	resolvedRetention = 1 * time.Hour
+)
+
+// State describes the possible states of an alert
+type State string
+
+const (
+	// StatePending means that the condition is true, but not for long enough to fire
+	StatePending State = "pending"
+
+	// StateFiring means that the condition has been true for long enough
+	StateFiring State = "firing"
+
+	// StateResolved means that the condition is no longer true, after the alert fired
+	StateResolved State = "resolved"
+)
+
+// Alert is the result of a rule for a specific object
+type Alert struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+	State     State  `json:"state"`
+
+	// ActiveSince is when the condition became true
+	ActiveSince time.Time `json:"activeSince"`
+
+	// FiringSince is when the alert started firing, or zero if it is still pending
+	FiringSince time.Time `json:"firingSince,omitempty"`
+
+	// ResolvedAt is when the alert has been resolved, or zero if it is still active
+	ResolvedAt time.Time `json:"resolvedAt,omitempty"`
+
+	// LastEvaluation is the last time the condition has been found true
+	LastEvaluation time.Time `json:"lastEvaluation"`
+}
+
+// Key uniquely identifies the alert of a rule for an object
+func (a *Alert) Key() string {
+	return a.Rule + "|" + a.Namespace + "|" + a.Kind + "|" + a.Name
+}
+
+// IsActive returns true if the alert is pending or firing
+func (a Alert) IsActive() bool {
+	return a.State == StatePending || a.State == StateFiring
+}
+
+// Engine evaluates the rules against the dashboard data,
+// and keeps track of the state of the alerts.
+type Engine struct {
+	rules []Rule
+
+	mutex          sync.RWMutex
+	alerts         map[string]*Alert
+	restarts       *restartHistory
+	lastEvaluation time.Time
+}
+
+// NewEngine builds a new Engine instance, for the given rules
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{
+		rules:    rules,
+		alerts:   make(map[string]*Alert),
+		restarts: newRestartHistory(),
+	}
+}
+
+// Rules returns the rules evaluated by the engine
+func (e *Engine) Rules() []Rule {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.rules
+}
+
+// SetRules replaces the rules of the engine.
+// The alerts of the removed rules will be resolved on the next evaluation.
+func (e *Engine) SetRules(rules []Rule) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.rules = rules
+}
+
+// LastEvaluation returns the time of the last evaluation of the rules
+func (e *Engine) LastEvaluation() time.Time {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.lastEvaluation
+}
+
+// Evaluate evaluates all the rules against the given data,
+// and updates the state of the alerts.
+// It can be registered as an api.RefreshListener.
+func (e *Engine) Evaluate(data *api.Data, now time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.restarts.record(data.Pods, now, e.maxRestartsWindow())
+
+	seen := make(map[string]bool)
+	for _, rule := range e.rules {
+		for _, v := range e.check(rule, data, now) {
+			alert := &Alert{
+				Rule:      rule.Name,
+				Severity:  rule.Severity,
+				Namespace: v.Namespace,
+				Kind:      v.Kind,
+				Name:      v.Name,
+			}
+			key := alert.Key()
+			seen[key] = true
+
+			if existing, found := e.alerts[key]; found && existing.IsActive() {
+				alert = existing
+			} else {
+				alert.State = StatePending
+				alert.ActiveSince = now
+				e.alerts[key] = alert
+			}
+			alert.Message = v.Message
+			alert.LastEvaluation = now
+
+			if alert.State == StatePending && now.Sub(alert.ActiveSince) >= time.Duration(rule.For) {
+				alert.State = StateFiring
+				alert.FiringSince = now
+				log.Printf("Alert %v is firing for %v %v/%v: %v", alert.Rule, alert.Kind, alert.Namespace, alert.Name, alert.Message)
+			}
+		}
+	}
+
+	for key, alert := range e.alerts {
+		if seen[key] {
+			continue
+		}
+		switch alert.State {
+		case StatePending:
+			// it never fired, so there is nothing to resolve
+			delete(e.alerts, key)
+		case StateFiring:
+			alert.State = StateResolved
+			alert.ResolvedAt = now
+			log.Printf("Alert %v is resolved for %v %v/%v", alert.Rule, alert.Kind, alert.Namespace, alert.Name)
+		case StateResolved:
+			if now.Sub(alert.ResolvedAt) > resolvedRetention {
+				delete(e.alerts, key)
+			}
+		}
+	}
+
+	e.lastEvaluation = now
+}
+
+// Alerts returns all the known alerts: the firing ones first, then the pending ones,
+// and then the recently resolved ones
+func (e *Engine) Alerts() []Alert {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	alerts := make([]Alert, 0, len(e.alerts))
+	for _, alert := range e.alerts {
+		alerts = append(alerts, *alert)
+	}
+	sort.Sort(AlertsByState(alerts))
+	return alerts
+}
+
+// ActiveAlerts returns the pending and firing alerts, the firing ones first
+func (e *Engine) ActiveAlerts() []Alert {
+	active := []Alert{}
+	for _, alert := range e.Alerts() {
+		if alert.IsActive() {
+			active = append(active, alert)
+		}
+	}
+	return active
+}
+
+// maxRestartsWindow returns the largest window of the pod restarts rules,
+// which is how long the restarts history needs to be kept
+func (e *Engine) maxRestartsWindow() time.Duration {
+	var window time.Duration
+	for _, rule := range e.rules {
+		if rule.Type == RuleTypePodRestarts && time.Duration(rule.Window) > window {
+			window = time.Duration(rule.Window)
+		}
+	}
+	return window
+}
+
+// AlertsByState is a slice of alerts, sortable by state (firing, pending, resolved),
+// then by severity, and then the most recent first
+type AlertsByState []Alert
+
+func (a AlertsByState) Len() int      { return len(a) }
+func (a AlertsByState) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a AlertsByState) Less(i, j int) bool {
+	if a[i].State != a[j].State {
+		return stateOrder[a[i].State] < stateOrder[a[j].State]
+	}
+	if a[i].Severity != a[j].Severity {
+		return a[i].Severity == SeverityCritical
+	}
+	return a[i].ActiveSince.After(a[j].ActiveSince)
+}
+
+var stateOrder = map[State]int{
+	StateFiring:   0,
+	StatePending:  1,
+	StateResolved: 2,
+}
@@ -0,0 +1,75 @@
+package alerts
+
+import (
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// restartSample is the restart count of a pod at a given time
+type restartSample struct {
+	Time     time.Time
+	Restarts int
+}
+
+// restartHistory keeps the restart counts of the pods over time,
+// to compute how many times a pod restarted during a window.
+type restartHistory struct {
+	samples map[string][]restartSample
+}
+
+func newRestartHistory() *restartHistory {
+	return &restartHistory{
+		samples: make(map[string][]restartSample),
+	}
+}
+
+// record records the current restart counts of the given pods,
+// and forgets the samples that are older than the given window (and the pods that are gone)
+func (h *restartHistory) record(pods []kapi.Pod, now time.Time, window time.Duration) {
+	current := make(map[string][]restartSample, len(pods))
+	for _, pod := range pods {
+		key := pod.Namespace + "/" + pod.Name
+		samples := append(h.samples[key], restartSample{Time: now, Restarts: api.RestartCountOf(pod)})
+
+		// keep the most recent sample before the window, as the baseline
+		first := 0
+		for i := range samples {
+			if samples[i].Time.After(now.Add(-window)) {
+				break
+			}
+			first = i
+		}
+		current[key] = samples[first:]
+	}
+	h.samples = current
+}
+
+// increase returns how many times the given pod restarted during the given window
+func (h *restartHistory) increase(pod kapi.Pod, window time.Duration, now time.Time) int {
+	samples := h.samples[pod.Namespace+"/"+pod.Name]
+	if len(samples) == 0 {
+		return 0
+	}
+	latest := samples[len(samples)-1].Restarts
+
+	// all the restarts of a pod created during the window happened during the window
+	if pod.CreationTimestamp.Time.After(now.Add(-window)) {
+		return latest
+	}
+
+	baseline := samples[0].Restarts
+	for _, sample := range samples {
+		if sample.Time.After(now.Add(-window)) {
+			break
+		}
+		baseline = sample.Restarts
+	}
+
+	if latest < baseline {
+		return latest
+	}
+	return latest - baseline
+}
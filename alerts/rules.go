@@ -0,0 +1,148 @@
+// Package alerts provides a rules engine, that evaluates alerting rules against the dashboard data
+package alerts
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/duration"
+
+	"github.com/ghodss/yaml"
+)
+
+// RuleType describes the possible types of rules
+type RuleType string
+
+const (
+	// RuleTypePodRestarts fires when the containers of a pod have restarted
+	// more than Threshold times during the Window
+	RuleTypePodRestarts RuleType = "pod-restarts"
+
+	// RuleTypeBuildFailureRate fires when more than Threshold percent of the builds of a BuildConfig
+	// that completed during the Window have failed
+	RuleTypeBuildFailureRate RuleType = "build-failure-rate"
+
+	// RuleTypeUnavailableReplicas fires when the latest deployment of a DeploymentConfig
+	// has less ready pods than desired replicas
+	RuleTypeUnavailableReplicas RuleType = "unavailable-replicas"
+
+	// RuleTypeRouteWithoutEndpoints fires when a route points to a service that has no endpoints
+	RuleTypeRouteWithoutEndpoints RuleType = "route-without-endpoints"
+)
+
+const (
+	// SeverityWarning is the default severity of the rules
+	SeverityWarning = "warning"
+
+	// SeverityCritical is the severity of the rules that need immediate attention
+	SeverityCritical = "critical"
+)
+
+// Rule is an alerting rule, evaluated against the dashboard data on each refresh
+type Rule struct {
+	// Name identifies the rule, and is displayed with its alerts
+	Name string `json:"name"`
+
+	// Type is the type of condition checked by the rule
+	Type RuleType `json:"type"`
+
+	// Severity is either "warning" (the default) or "critical"
+	Severity string `json:"severity,omitempty"`
+
+	// Threshold is the limit above which the rule fires (a number of restarts, a percentage, ...)
+	Threshold float64 `json:"threshold,omitempty"`
+
+	// Window is the period of time over which the condition is checked (for restarts and builds)
+	Window duration.Duration `json:"window,omitempty"`
+
+	// For is the duration during which the condition must be true before the alert fires.
+	// Until then, the alert is pending.
+	For duration.Duration `json:"for,omitempty"`
+
+	// Namespaces restricts the rule to the given namespaces (projects), or to all namespaces if empty
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// RulesFile is the content of a rules file
+type RulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadRules reads the rules from the given (YAML or JSON) file,
+// and validates them
+func LoadRules(path string) ([]Rule, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesFile := &RulesFile{}
+	if err := yaml.Unmarshal(content, rulesFile); err != nil {
+		return nil, fmt.Errorf("Failed to parse the rules file %v: %v", path, err)
+	}
+
+	names := make(map[string]bool)
+	for i := range rulesFile.Rules {
+		rule := &rulesFile.Rules[i]
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("Duplicate rule %v!", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+
+	return rulesFile.Rules, nil
+}
+
+// validate checks that the rule is valid, and sets the default values
+func (r *Rule) validate() error {
+	if len(r.Name) == 0 {
+		return fmt.Errorf("Missing name for rule of type %v!", r.Type)
+	}
+
+	switch r.Type {
+	case RuleTypePodRestarts:
+		if r.Threshold == 0 {
+			r.Threshold = 5
+		}
+		if r.Window == 0 {
+			r.Window = duration.Duration(10 * time.Minute)
+		}
+	case RuleTypeBuildFailureRate:
+		if r.Threshold == 0 {
+			r.Threshold = 50
+		}
+		if r.Window == 0 {
+			r.Window = duration.Duration(24 * time.Hour)
+		}
+	case RuleTypeUnavailableReplicas, RuleTypeRouteWithoutEndpoints:
+	default:
+		return fmt.Errorf("Unknown type %v for rule %v!", r.Type, r.Name)
+	}
+
+	switch r.Severity {
+	case "":
+		r.Severity = SeverityWarning
+	case SeverityWarning, SeverityCritical:
+	default:
+		return fmt.Errorf("Unknown severity %v for rule %v!", r.Severity, r.Name)
+	}
+
+	return nil
+}
+
+// appliesTo returns true if the rule should be evaluated for the objects of the given namespace
+func (r *Rule) appliesTo(namespace string) bool {
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range r.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+
+	"k8s.io/kubernetes/pkg/util"
+
+	"github.com/pmylund/go-cache"
+)
+
+const (
+	// AuthenticatedGroup is the virtual group that all the authenticated users belong to
+	AuthenticatedGroup = "system:authenticated"
+)
+
+// Identity is a user (with its groups), on behalf of whom a ClientWrapper can retrieve data
+// while using the credentials of another user (such as the dashboard's service account).
+type Identity struct {
+	User   string
+	Groups []string
+}
+
+// ForIdentity builds a new ClientWrapper instance that uses the same credentials as this instance,
+// but only retrieves data from the namespaces the given identity has access to.
+// The new instance has its own caches, and uses caching only if this instance does.
+// The access to the namespaces is checked with ResourceAccessReviews, which requires
+// the credentials of this instance to be allowed to create ResourceAccessReviews.
+func (cw *ClientWrapper) ForIdentity(identity Identity) *ClientWrapper {
+	var resourcesCache *cache.Cache
+	if cw.resourcesCache != nil {
+		resourcesCache = newCache()
+	}
+
+	return &ClientWrapper{
+		factory:            cw.factory,
+		resourcesCache:     resourcesCache,
+		namespacesCache:    newCache(),
+		permissionsCache:   newCache(),
+		identity:           &identity,
+		accessReviewsCache: cw.accessReviewsCache,
+		snapshots:          newSnapshotStore(),
+		options:            cw.options,
+	}
+}
+
+// filterAccessibleNamespaces returns the namespaces (from the given ones)
+// that the identity of this ClientWrapper has access to.
+func (cw *ClientWrapper) filterAccessibleNamespaces(namespaces []string) ([]string, error) {
+	accessible := []string{}
+	for _, namespace := range namespaces {
+		review, err := cw.reviewNamespaceAccess(namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		if cw.identity.isAllowedBy(review) {
+			accessible = append(accessible, namespace)
+		}
+	}
+	return accessible, nil
+}
+
+// reviewNamespaceAccess returns the users and groups who can access the given namespace.
+func (cw *ClientWrapper) reviewNamespaceAccess(namespace string) (*authorizationapi.ResourceAccessReviewResponse, error) {
+	if review, found := cw.accessReviewsCache.Get(namespace); found {
+		return review.(*authorizationapi.ResourceAccessReviewResponse), nil
+	}
+
+	client, _, err := cw.factory.Clients()
+	if err != nil {
+		return nil, err
+	}
+
+	// same review as the one used by OpenShift to decide who can see a project
+	review, err := client.ResourceAccessReviews(namespace).Create(&authorizationapi.ResourceAccessReview{
+		Verb:     "get",
+		Resource: "namespaces",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	setCached(cw.accessReviewsCache, namespace, review, cw.Options().PermissionsCacheTTL)
+	return review, nil
+}
+
+// isAllowedBy returns true if the given review allows this identity,
+// either directly or through one of its groups.
+func (i *Identity) isAllowedBy(review *authorizationapi.ResourceAccessReviewResponse) bool {
+	if review.Users.Has(i.User) {
+		return true
+	}
+	if review.Groups.Has(AuthenticatedGroup) {
+		return true
+	}
+	for _, group := range i.Groups {
+		if review.Groups.Has(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanI returns true if the authenticated user (or the identity of this ClientWrapper, if any)
+// is allowed to perform the given verb on the given resource in the given namespace.
+// The permissions are checked with SubjectAccessReviews.
+func (cw *ClientWrapper) CanI(namespace string, verb string, resource string) (bool, error) {
+	key := namespace + "/" + verb + "/" + resource
+	if allowed, found := cw.permissionsCache.Get(key); found {
+		return allowed.(bool), nil
+	}
+
+	client, _, err := cw.factory.Clients()
+	if err != nil {
+		return false, err
+	}
+
+	review := &authorizationapi.SubjectAccessReview{
+		Verb:     verb,
+		Resource: resource,
+	}
+	if cw.identity != nil {
+		review.User = cw.identity.User
+		review.Groups = util.NewStringSet(cw.identity.Groups...)
+		review.Groups.Insert(AuthenticatedGroup)
+	}
+
+	response, err := client.SubjectAccessReviews(namespace).Create(review)
+	if err != nil {
+		return false, err
+	}
+
+	setCached(cw.permissionsCache, key, response.Allowed, cw.Options().PermissionsCacheTTL)
+	return response.Allowed, nil
+}
@@ -1,14 +1,19 @@
 package api
 
+import (
+	"sort"
+)
+
 const (
-	// ApplicationNameLabel is the name of the label used to store the application
+	// ApplicationNameLabel is the name of the default label used to store the application
+	// (see Grouping for the labels used instead)
 	ApplicationNameLabel = "application"
 )
 
 // Application is a concept used here to group multiple objects together
 // It is different from the project, because the same project can be used for multiple applications
 // Or some projects can have resources (like BuildConfigs and ImageStreams) but no applications.
-// Objects are linked to an application by a label.
+// Objects are linked to an application by a label (see Grouping).
 type Application string
 
 // Name returns the name of the application
@@ -24,3 +29,79 @@ type Applications []Application
 func (apps Applications) Len() int           { return len(apps) }
 func (apps Applications) Less(i, j int) bool { return apps[i] < apps[j] }
 func (apps Applications) Swap(i, j int)      { apps[i], apps[j] = apps[j], apps[i] }
+
+// ForApplication returns a new Data instance, with only the objects of this instance
+// that belongs to the given application.
+func (d *Data) ForApplication(application string) *Data {
+	idx := d.indexed()
+	labelled := idx.byApplication[application]
+
+	p := make(positions)
+	for _, resourceType := range []ResourceType{
+		ResourceTypeRoute,
+		ResourceTypeService,
+		ResourceTypePod,
+		ResourceTypeImageStream,
+		ResourceTypeBuildConfig,
+		ResourceTypeDeploymentConfig,
+		ResourceTypeReplicationController,
+	} {
+		p[resourceType] = labelled[resourceType]
+	}
+
+	endpoints := make(map[int]bool)
+	for _, i := range labelled[ResourceTypeService] {
+		// the endpoints have the same name as their service
+		if j, found := idx.find(ResourceTypeEndpoints, d.Services[i].Namespace, d.Services[i].Name); found {
+			endpoints[j] = true
+		}
+	}
+	p[ResourceTypeEndpoints] = sortedPositions(endpoints)
+
+	builds := make(map[int]bool)
+	for _, i := range labelled[ResourceTypeBuild] {
+		builds[i] = true
+	}
+	for _, i := range labelled[ResourceTypeBuildConfig] {
+		for _, j := range idx.buildsByBC[d.BuildConfigs[i].Namespace+"/"+d.BuildConfigs[i].Name] {
+			builds[j] = true
+		}
+	}
+	p[ResourceTypeBuild] = sortedPositions(builds)
+
+	// the projects in which the application has at least one BuildConfig, DeploymentConfig, Service or Route
+	namespaces := make(map[string]bool)
+	for _, i := range labelled[ResourceTypeBuildConfig] {
+		namespaces[d.BuildConfigs[i].Namespace] = true
+	}
+	for _, i := range labelled[ResourceTypeDeploymentConfig] {
+		namespaces[d.DeploymentConfigs[i].Namespace] = true
+	}
+	for _, i := range labelled[ResourceTypeService] {
+		namespaces[d.Services[i].Namespace] = true
+	}
+	for _, i := range labelled[ResourceTypeRoute] {
+		namespaces[d.Routes[i].Namespace] = true
+	}
+	projects := make(map[int]bool)
+	for namespace := range namespaces {
+		if i, found := idx.find(ResourceTypeProject, "", namespace); found {
+			projects[i] = true
+		}
+	}
+	p[ResourceTypeProject] = sortedPositions(projects)
+
+	app := d.subset(p)
+	app.Applications = []Application{Application(application)}
+	return app
+}
+
+// sortedPositions returns the given positions, sorted
+func sortedPositions(set map[int]bool) []int {
+	sorted := []int{}
+	for i := range set {
+		sorted = append(sorted, i)
+	}
+	sort.Ints(sorted)
+	return sorted
+}
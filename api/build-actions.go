@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// StartBuild starts a new build from the given BuildConfig
+// It returns the new build.
+func (cw *ClientWrapper) StartBuild(namespace string, buildConfigName string) (*buildapi.Build, error) {
+	client, _, err := cw.factory.Clients()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.BuildConfigs(namespace).Instantiate(&buildapi.BuildRequest{
+		ObjectMeta: kapi.ObjectMeta{Name: buildConfigName},
+	})
+}
+
+// RerunBuild starts a new build, with the same configuration as the given build.
+// It returns the new build.
+func (cw *ClientWrapper) RerunBuild(namespace string, buildName string) (*buildapi.Build, error) {
+	client, _, err := cw.factory.Clients()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Builds(namespace).Clone(&buildapi.BuildRequest{
+		ObjectMeta: kapi.ObjectMeta{Name: buildName},
+	})
+}
+
+// CancelBuild cancels the given build, if it is not already finished.
+func (cw *ClientWrapper) CancelBuild(namespace string, buildName string) error {
+	client, _, err := cw.factory.Clients()
+	if err != nil {
+		return err
+	}
+
+	build, err := client.Builds(namespace).Get(buildName)
+	if err != nil {
+		return err
+	}
+
+	if IsBuildFinished(*build) {
+		return fmt.Errorf("Build %s/%s is already finished (%s)!", namespace, buildName, build.Status.Phase)
+	}
+
+	build.Status.Cancelled = true
+	_, err = client.Builds(namespace).Update(build)
+	return err
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"sort"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+)
+
+// BuildConfigNameOf returns the name of the BuildConfig that created the given build,
+// or an empty string if it has not been created by a BuildConfig
+func BuildConfigNameOf(build buildapi.Build) string {
+	if build.Status.Config != nil {
+		return build.Status.Config.Name
+	}
+	return build.Labels[buildapi.BuildConfigLabel]
+}
+
+// IsBuildFinished returns true if the given build is finished,
+// whatever its result: it can't be cancelled anymore
+func IsBuildFinished(build buildapi.Build) bool {
+	switch build.Status.Phase {
+	case buildapi.BuildPhaseComplete, buildapi.BuildPhaseFailed, buildapi.BuildPhaseError, buildapi.BuildPhaseCancelled:
+		return true
+	}
+	return false
+}
+
+// BuildsOf returns the builds created by the given BuildConfig, the most recent first
+func (d *Data) BuildsOf(bc buildapi.BuildConfig) []buildapi.Build {
+	builds := []buildapi.Build{}
+	for _, i := range d.indexed().buildsByBC[bc.Namespace+"/"+bc.Name] {
+		builds = append(builds, d.Builds[i])
+	}
+	sort.Sort(sort.Reverse(BuildsByCreationTimestamp(builds)))
+	return builds
+}
+
+// FindBuildConfig returns the BuildConfig with the given namespace and name
+func (d *Data) FindBuildConfig(namespace string, name string) (*buildapi.BuildConfig, bool) {
+	if i, found := d.indexed().find(ResourceTypeBuildConfig, namespace, name); found {
+		return &d.BuildConfigs[i], true
+	}
+	return nil, false
+}
+
+// FindBuild returns the Build with the given namespace and name
+func (d *Data) FindBuild(namespace string, name string) (*buildapi.Build, bool) {
+	if i, found := d.indexed().find(ResourceTypeBuild, namespace, name); found {
+		return &d.Builds[i], true
+	}
+	return nil, false
+}
+
+// BuildsByCreationTimestamp is a slice of builds
+// used for sorting builds by creation timestamp
+type BuildsByCreationTimestamp []buildapi.Build
+
+func (b BuildsByCreationTimestamp) Len() int      { return len(b) }
+func (b BuildsByCreationTimestamp) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b BuildsByCreationTimestamp) Less(i, j int) bool {
+	return b[i].CreationTimestamp.Before(b[j].CreationTimestamp)
+}
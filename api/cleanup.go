@@ -0,0 +1,352 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+// CleanupPolicy describes when the resources are considered as unused
+type CleanupPolicy struct {
+	// StaleBuildConfigs is how long a BuildConfig can go without a build before it is considered as unused
+	StaleBuildConfigs time.Duration
+
+	// BuildsRetention is the number of finished builds kept for each BuildConfig
+	BuildsRetention int
+
+	// ExcludedProjects are the patterns of the projects that are never cleaned up (such as "openshift-*"),
+	// or the default ones if nil
+	ExcludedProjects []string
+}
+
+// DefaultCleanupExcludedProjects are the patterns of the system and infra projects, which are never cleaned up
+var DefaultCleanupExcludedProjects = []string{"default", "openshift", "openshift-*", "kube-*", "management-infra"}
+
+const (
+	// DefaultStaleBuildConfigs is how long a BuildConfig can go without a build, if the policy doesn't define it
+	DefaultStaleBuildConfigs = 90 * 24 * time.Hour
+
+	// DefaultBuildsRetention is the number of finished builds kept for each BuildConfig, if the policy doesn't define it
+	DefaultBuildsRetention = 5
+)
+
+// staleBuildConfigs returns how long a BuildConfig can go without a build
+func (p CleanupPolicy) staleBuildConfigs() time.Duration {
+	if p.StaleBuildConfigs <= 0 {
+		return DefaultStaleBuildConfigs
+	}
+	return p.StaleBuildConfigs
+}
+
+// buildsRetention returns the number of finished builds kept for each BuildConfig
+func (p CleanupPolicy) buildsRetention() int {
+	if p.BuildsRetention <= 0 {
+		return DefaultBuildsRetention
+	}
+	return p.BuildsRetention
+}
+
+// isExcludedProject returns true if the given project is never cleaned up
+func (p CleanupPolicy) isExcludedProject(project string) bool {
+	if p.ExcludedProjects == nil {
+		return matchesAny(DefaultCleanupExcludedProjects, project)
+	}
+	return matchesAny(p.ExcludedProjects, project)
+}
+
+// Thresholds returns how many days a BuildConfig can go without a build,
+// and the number of finished builds kept for each BuildConfig
+func (p CleanupPolicy) Thresholds() (int, int) {
+	return int(p.staleBuildConfigs().Hours() / 24), p.buildsRetention()
+}
+
+// CleanupCandidate is a resource that is probably garbage, and could be deleted to reclaim quota
+type CleanupCandidate struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Reason is why the resource is probably garbage, such as "service myapp not found"
+	Reason string `json:"reason"`
+}
+
+// CleanupKinds are the kinds of the cleanup candidates, in the order of the reports.
+// The "IdleProject" candidates are the projects that are not empty, but seem unused: they are only reported, without command.
+var CleanupKinds = []string{"Project", "IdleProject", "Route", "Service", "ReplicationController", "BuildConfig", "Build", "ImageStream"}
+
+// cleanupShortNames are the short names of the kinds of the cleanup candidates, for the "oc delete" commands
+var cleanupShortNames = map[string]string{
+	"Route":                 "route",
+	"Service":               "svc",
+	"ReplicationController": "rc",
+	"BuildConfig":           "bc",
+	"Build":                 "build",
+	"ImageStream":           "is",
+}
+
+// Command returns the "oc delete" command that deletes the resource,
+// or an empty string if the resource is only reported (such as the idle projects)
+func (c CleanupCandidate) Command() string {
+	switch c.Kind {
+	case "Project":
+		return "oc delete project " + c.Name
+	case "IdleProject":
+		return ""
+	}
+	return fmt.Sprintf("oc delete %s/%s -n %s", cleanupShortNames[c.Kind], c.Name, c.Namespace)
+}
+
+// CleanupCandidates finds the resources of this Data instance that are probably garbage, at the given time,
+// sorted by project (and then by kind and name):
+// the empty projects (without any object of the loaded types), the idle projects (without running pods,
+// BuildConfigs, DeploymentConfigs nor ImageStreams, but not empty), the routes to missing services,
+// the services without route that select no pods, nor the pods of a DeploymentConfig (which may be scaled down),
+// the ReplicationControllers of deleted DeploymentConfigs, the BuildConfigs that haven't built for a while,
+// the finished builds past the retention (or of deleted BuildConfigs), and the ImageStreams no one references.
+// The resources are only checked against the types that have been loaded,
+// and the resources of the excluded (system and infra) projects are never reported.
+func (d *Data) CleanupCandidates(now time.Time) []CleanupCandidate {
+	candidates := []CleanupCandidate{}
+	candidate := func(kind string, meta kapi.ObjectMeta, format string, args ...interface{}) {
+		if d.CleanupPolicy.isExcludedProject(meta.Namespace) {
+			return
+		}
+		candidates = append(candidates, CleanupCandidate{
+			Kind:      kind,
+			Namespace: meta.Namespace,
+			Name:      meta.Name,
+			Reason:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	emptyProjects := make(map[string]bool)
+
+	// the other objects (such as the persistent volume claims) are not loaded: only the projects without any object
+	// of all the loaded types are reported, and the reason says what has not been checked
+	if d.Routes != nil && d.Services != nil && d.Pods != nil && d.ReplicationControllers != nil &&
+		d.BuildConfigs != nil && d.Builds != nil && d.DeploymentConfigs != nil && d.ImageStreams != nil {
+		for _, project := range d.Projects {
+			empty := true
+			for resourceType, objects := range d.indexed().byNamespace[project.Name] {
+				if resourceType != ResourceTypeEvent && len(objects) > 0 {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				emptyProjects[project.Name] = true
+				candidate("Project", kapi.ObjectMeta{Namespace: project.Name, Name: project.Name},
+					"no routes, services, pods, ReplicationControllers, BuildConfigs, builds, DeploymentConfigs nor ImageStreams "+
+						"(the persistent volume claims, secrets and config maps have not been checked)")
+			}
+		}
+	}
+
+	// the builder and deployer pods are not loaded: a project that only builds images is not idle.
+	// The idle projects still have objects (such as stopped pods or services), so they are only reported, without command
+	if d.Pods != nil && d.BuildConfigs != nil && d.DeploymentConfigs != nil && d.ImageStreams != nil {
+		for _, project := range d.Projects {
+			if emptyProjects[project.Name] {
+				continue
+			}
+			objects := d.indexed().byNamespace[project.Name]
+			if len(objects[ResourceTypeBuildConfig]) > 0 || len(objects[ResourceTypeDeploymentConfig]) > 0 || len(objects[ResourceTypeImageStream]) > 0 {
+				continue
+			}
+			running := 0
+			for _, i := range objects[ResourceTypePod] {
+				if d.Pods[i].Status.Phase == kapi.PodRunning {
+					running++
+				}
+			}
+			if running == 0 {
+				candidate("IdleProject", kapi.ObjectMeta{Namespace: project.Name, Name: project.Name},
+					"no running pods, BuildConfigs, DeploymentConfigs nor ImageStreams, but not empty: check its objects before deleting it")
+			}
+		}
+	}
+
+	if d.Services != nil {
+		for _, route := range d.Routes {
+			if _, found := d.indexed().find(ResourceTypeService, route.Namespace, route.ServiceName); !found {
+				candidate("Route", route.ObjectMeta, "service %v not found", route.ServiceName)
+			}
+		}
+	}
+
+	if d.Routes != nil {
+		routed := make(map[string]bool)
+		for _, route := range d.Routes {
+			routed[route.Namespace+"/"+route.ServiceName] = true
+		}
+		for _, report := range d.ServiceReports() {
+			service := report.Service
+			if routed[service.Namespace+"/"+service.Name] || report.Pods > 0 {
+				continue
+			}
+			switch {
+			case len(service.Spec.Selector) > 0:
+				// the services of the DeploymentConfigs scaled down to 0 select no pods, but are still used
+				if d.DeploymentConfigs != nil && !d.selectsDeploymentConfig(service) {
+					candidate("Service", service.ObjectMeta, "no route, and selects no pods nor DeploymentConfigs")
+				}
+			case report.Endpoints == 0:
+				candidate("Service", service.ObjectMeta, "no route, and no endpoints")
+			}
+		}
+	}
+
+	if d.DeploymentConfigs != nil {
+		for _, rc := range d.ReplicationControllers {
+			if dc := DeploymentConfigNameOf(rc); len(dc) > 0 {
+				if _, found := d.FindDeploymentConfig(rc.Namespace, dc); !found {
+					candidate("ReplicationController", rc.ObjectMeta, "DeploymentConfig %v not found", dc)
+				}
+			}
+		}
+	}
+
+	if d.Builds != nil {
+		for _, bc := range d.BuildConfigs {
+			builds := d.BuildsOf(bc)
+			if len(builds) == 0 {
+				if age := now.Sub(bc.CreationTimestamp.Time); age > d.CleanupPolicy.staleBuildConfigs() {
+					candidate("BuildConfig", bc.ObjectMeta, "never built, created %v ago", pluralize(int(age.Hours()/24), "day"))
+				}
+			} else if age := now.Sub(builds[0].CreationTimestamp.Time); age > d.CleanupPolicy.staleBuildConfigs() {
+				candidate("BuildConfig", bc.ObjectMeta, "no build for %v", pluralize(int(age.Hours()/24), "day"))
+			}
+
+			finished := 0
+			for _, build := range builds {
+				if !IsBuildFinished(build) {
+					continue
+				}
+				if finished++; finished > d.CleanupPolicy.buildsRetention() {
+					candidate("Build", build.ObjectMeta, "%v build, past the %v of %v", build.Status.Phase,
+						pluralize(d.CleanupPolicy.buildsRetention(), "last finished build"), bc.Name)
+				}
+			}
+		}
+		if d.BuildConfigs != nil {
+			for _, build := range d.Builds {
+				if bc := BuildConfigNameOf(build); len(bc) > 0 && IsBuildFinished(build) {
+					if _, found := d.FindBuildConfig(build.Namespace, bc); !found {
+						candidate("Build", build.ObjectMeta, "BuildConfig %v not found", bc)
+					}
+				}
+			}
+		}
+	}
+
+	if d.BuildConfigs != nil && d.DeploymentConfigs != nil {
+		referenced := d.referencedImageStreams()
+		for _, is := range d.ImageStreams {
+			if !referenced[is.Namespace+"/"+is.Name] {
+				candidate("ImageStream", is.ObjectMeta, "not used by any BuildConfig or DeploymentConfig")
+			}
+		}
+	}
+
+	sort.Sort(cleanupCandidatesByProject(candidates))
+	return candidates
+}
+
+// selectsDeploymentConfig returns true if the selector of the given service
+// matches the labels of the pod template of a DeploymentConfig of its namespace
+func (d *Data) selectsDeploymentConfig(service kapi.Service) bool {
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for _, i := range d.indexed().byNamespace[service.Namespace][ResourceTypeDeploymentConfig] {
+		if template := d.DeploymentConfigs[i].Template.ControllerTemplate.Template; template != nil && selector.Matches(labels.Set(template.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencedImageStreams returns the ImageStreams ("namespace/name") referenced by the BuildConfigs
+// (their base image, image change triggers and output), by the DeploymentConfigs (their image change triggers
+// and the images of their containers), and by the tags of the other ImageStreams
+func (d *Data) referencedImageStreams() map[string]bool {
+	referenced := make(map[string]bool)
+	reference := func(from kapi.ObjectReference, namespace string) {
+		repository, _ := repositoryAndTagOf(imageKey(from, namespace))
+		referenced[repository] = true
+	}
+
+	for _, bc := range d.BuildConfigs {
+		if from := strategyFromOf(bc.Spec.Strategy); from != nil {
+			reference(*from, bc.Namespace)
+		}
+		for _, trigger := range bc.Spec.Triggers {
+			if trigger.ImageChange != nil && trigger.ImageChange.From != nil {
+				reference(*trigger.ImageChange.From, bc.Namespace)
+			}
+		}
+		if to := bc.Spec.Output.To; to != nil {
+			reference(*to, bc.Namespace)
+		}
+	}
+
+	for _, dc := range d.DeploymentConfigs {
+		for _, trigger := range dc.Triggers {
+			if trigger.Type == deployapi.DeploymentTriggerOnImageChange && trigger.ImageChangeParams != nil {
+				from := trigger.ImageChangeParams.From
+				if len(from.Kind) == 0 {
+					// the deprecated form of the trigger
+					from.Kind = "ImageStream"
+				}
+				reference(from, dc.Namespace)
+			}
+		}
+		if template := dc.Template.ControllerTemplate.Template; template != nil {
+			for _, container := range template.Spec.Containers {
+				// the images pulled from the internal registry, such as "172.30.1.1:5000/myproject/myapp@sha256:..."
+				repository, _ := repositoryAndTagOf(container.Image)
+				referenced[repository] = true
+			}
+		}
+	}
+
+	for _, is := range d.ImageStreams {
+		for _, tag := range is.Spec.Tags {
+			if tag.From == nil || tag.From.Kind == "DockerImage" {
+				continue
+			}
+			if repository, _ := repositoryAndTagOf(imageKey(*tag.From, is.Namespace)); repository != is.Namespace+"/"+is.Name {
+				referenced[repository] = true
+			}
+		}
+	}
+	return referenced
+}
+
+// cleanupCandidatesByProject sorts the cleanup candidates by project, and then by kind (in the order of the reports) and name
+type cleanupCandidatesByProject []CleanupCandidate
+
+func (c cleanupCandidatesByProject) Len() int      { return len(c) }
+func (c cleanupCandidatesByProject) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c cleanupCandidatesByProject) Less(i, j int) bool {
+	if c[i].Namespace != c[j].Namespace {
+		return c[i].Namespace < c[j].Namespace
+	}
+	if ki, kj := cleanupKindOrder(c[i].Kind), cleanupKindOrder(c[j].Kind); ki != kj {
+		return ki < kj
+	}
+	return c[i].Name < c[j].Name
+}
+
+// cleanupKindOrder returns the position of the given kind in the reports
+func cleanupKindOrder(kind string) int {
+	for i, k := range CleanupKinds {
+		if k == kind {
+			return i
+		}
+	}
+	return len(CleanupKinds)
+}
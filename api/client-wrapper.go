@@ -3,10 +3,12 @@ package api
 import (
 	"fmt"
 	"log"
+	"os"
 	"reflect"
 	"time"
 
 	"github.com/openshift/origin/pkg/cmd/util/clientcmd"
+	userapi "github.com/openshift/origin/pkg/user/api"
 
 	k8client "k8s.io/kubernetes/pkg/client"
 	kclientcmd "k8s.io/kubernetes/pkg/client/clientcmd"
@@ -26,32 +28,124 @@ type ClientWrapper struct {
 	factory         *clientcmd.Factory
 	namespacesCache *cache.Cache
 	resourcesCache  *cache.Cache
+
+	// identity is the user on behalf of whom the data are retrieved,
+	// or nil if the data are retrieved for the authenticated user
+	identity *Identity
+
+	// permissionsCache stores the results of the SubjectAccessReviews
+	permissionsCache *cache.Cache
+
+	// accessReviewsCache stores the results of the ResourceAccessReviews, per namespace
+	// it is shared by all the ClientWrapper instances that use the same credentials
+	accessReviewsCache *cache.Cache
+
+	// snapshots stores the latest snapshot of the data, served to the pages
+	// it is shared by all the ClientWrapper instances that use the same credentials and identity
+	snapshots *snapshotStore
+
+	// options are shared by all the ClientWrapper instances derived from the same instance
+	options *sharedOptions
 }
 
 // NewClientWrapper build a new ClientWrapper instance
-// with or without caching
-func NewClientWrapper(withCache bool) *ClientWrapper {
-	factory := getFactory()
+// connected with the given connection, with or without caching
+func NewClientWrapper(withCache bool, connection Connection, options Options) (*ClientWrapper, error) {
+	factory, err := getFactory(connection)
+	if err != nil {
+		return nil, err
+	}
 
 	var resourcesCache *cache.Cache
 	if withCache {
-		resourcesCache = cache.New(5*time.Minute, 30*time.Second)
+		resourcesCache = newCache()
 	} else {
 		resourcesCache = nil
 	}
 
 	return &ClientWrapper{
-		factory:         factory,
-		resourcesCache:  resourcesCache,
-		namespacesCache: cache.New(5*time.Minute, 30*time.Second),
+		factory:            factory,
+		resourcesCache:     resourcesCache,
+		namespacesCache:    newCache(),
+		permissionsCache:   newCache(),
+		accessReviewsCache: newCache(),
+		snapshots:          newSnapshotStore(),
+		options:            &sharedOptions{options: options},
+	}, nil
+}
+
+// newCache builds a new cache, whose entries are stored with the TTL from the options
+func newCache() *cache.Cache {
+	return cache.New(cache.NoExpiration, 30*time.Second)
+}
+
+// setCached stores the given value in the given cache, for the given TTL (if it is positive)
+func setCached(c *cache.Cache, key string, value interface{}, ttl time.Duration) {
+	if ttl > 0 {
+		c.Set(key, value, ttl)
+	}
+}
+
+// ForToken builds a new ClientWrapper instance that connects to the same API Server,
+// but authenticates with the given (OAuth) token instead.
+// The new instance has its own caches, and uses caching only if this instance does.
+func (cw *ClientWrapper) ForToken(token string) (*ClientWrapper, error) {
+	factory, err := getFactoryForToken(cw.factory, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourcesCache *cache.Cache
+	if cw.resourcesCache != nil {
+		resourcesCache = newCache()
+	}
+
+	return &ClientWrapper{
+		factory:            factory,
+		resourcesCache:     resourcesCache,
+		namespacesCache:    newCache(),
+		permissionsCache:   newCache(),
+		accessReviewsCache: newCache(),
+		snapshots:          newSnapshotStore(),
+		options:            cw.options,
+	}, nil
+}
+
+// withoutResourcesCache returns a ClientWrapper instance that uses the same credentials, caches and snapshots
+// as this instance, except for the resources, which are always retrieved from the API.
+func (cw *ClientWrapper) withoutResourcesCache() *ClientWrapper {
+	return &ClientWrapper{
+		factory:            cw.factory,
+		namespacesCache:    cw.namespacesCache,
+		identity:           cw.identity,
+		permissionsCache:   cw.permissionsCache,
+		accessReviewsCache: cw.accessReviewsCache,
+		snapshots:          cw.snapshots,
+		options:            cw.options,
 	}
 }
 
+// CurrentUser retrieves the user that is authenticated by this ClientWrapper.
+func (cw *ClientWrapper) CurrentUser() (*userapi.User, error) {
+	client, _, err := cw.factory.Clients()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Users().Get("~")
+}
+
+// ClientConfig returns the configuration used to connect to the API Server.
+func (cw *ClientWrapper) ClientConfig() (*k8client.Config, error) {
+	return cw.factory.OpenShiftClientConfig.ClientConfig()
+}
+
 // LoadData build a Data instance, populated with data for the given resource types.
 // You can use ResourceTypeAll to get data for all resources types.
 // If caching is enabled, it will use the cache if there are fresh data in it.
 func (cw *ClientWrapper) LoadData(resourceTypes ...ResourceType) (*Data, error) {
-	data := &Data{}
+	options := cw.Options()
+	data := &Data{Grouping: options.Grouping, RoutePolicy: options.RoutePolicy, CleanupPolicy: options.CleanupPolicy}
 
 	namespaces, err := cw.GetAvailableNamespaces()
 	if err != nil {
@@ -68,10 +162,14 @@ func (cw *ClientWrapper) LoadData(resourceTypes ...ResourceType) (*Data, error)
 		case ResourceTypeProject:
 			channels = append(channels, cw.AsyncListResources(resourceType, "openshift"))
 		default:
+			if !options.IsResourceTypeEnabled(resourceType) {
+				continue
+			}
 			channels = append(channels, cw.AsyncListResources(resourceType, namespaces...))
 		}
 	}
 
+	timeout := time.After(options.LoadTimeout)
 	for _, channel := range channels {
 		select {
 		case d := <-channel:
@@ -79,29 +177,34 @@ func (cw *ClientWrapper) LoadData(resourceTypes ...ResourceType) (*Data, error)
 				return nil, fmt.Errorf("Failed to load data: %v", d.Errors)
 			}
 			data.Merge(d.Data)
-		case <-time.After(10 * time.Second):
+		case <-timeout:
 			return nil, fmt.Errorf("Timed out while loading data!")
 		}
 	}
 
 	for _, resourceType := range resourceTypes {
 		switch resourceType {
+		case ResourceTypeProject:
+			data.RetainProjects(namespaces)
 		case ResourceTypePod:
 			data.RemoveBuilderAndDeployerPods()
 		case ResourceTypeContainer:
 			data.ExtractContainersFromPods()
 		case ResourceTypeApplication:
-			data.ExtractApplicationsFromDeploymentConfigs()
+			data.ExtractApplications()
 		}
 	}
 
+	data.BuildIndex()
 	return data, nil
 }
 
 // GetAvailableNamespaces retrieves all available namespaces.
+// Only the projects selected by the options are returned.
 func (cw *ClientWrapper) GetAvailableNamespaces() ([]string, error) {
+	options := cw.Options()
 	if namespaces, found := cw.namespacesCache.Get("namespaces"); found {
-		return namespaces.([]string), nil
+		return selectProjects(namespaces.([]string), &options), nil
 	}
 
 	client, _, err := cw.factory.Clients()
@@ -119,8 +222,26 @@ func (cw *ClientWrapper) GetAvailableNamespaces() ([]string, error) {
 		namespaces = append(namespaces, project.Name)
 	}
 
-	cw.namespacesCache.Set("namespaces", namespaces, cache.DefaultExpiration)
-	return namespaces, nil
+	if cw.identity != nil {
+		namespaces, err = cw.filterAccessibleNamespaces(namespaces)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	setCached(cw.namespacesCache, "namespaces", namespaces, options.NamespacesCacheTTL)
+	return selectProjects(namespaces, &options), nil
+}
+
+// selectProjects returns the namespaces (from the given ones) selected by the given options
+func selectProjects(namespaces []string, options *Options) []string {
+	selected := []string{}
+	for _, namespace := range namespaces {
+		if options.IsProjectSelected(namespace) {
+			selected = append(selected, namespace)
+		}
+	}
+	return selected
 }
 
 // AsyncListResources retrieves the list of resources for the given resource type.
@@ -191,7 +312,7 @@ func (cw *ClientWrapper) ListResources(resourceType ResourceType, namespaces ...
 	}
 
 	if cw.resourcesCache != nil {
-		cw.resourcesCache.Set(string(resourceType), results, cache.DefaultExpiration)
+		setCached(cw.resourcesCache, string(resourceType), results, cw.Options().ResourcesCacheTTL)
 	}
 	return results, nil
 }
@@ -258,16 +379,40 @@ func extractItems(obj runtime.Object) ([]interface{}, error) {
 }
 
 // getFactory returns an OpenShift's Factory
-// It first tries to use the config that is made available when we are running in a cluster
+// If the given connection defines a kubeconfig file or a context, it uses them.
+// Otherwise it first tries to use the config that is made available when we are running in a cluster
 // and then fallback to a standard factory (using the default config files)
-func getFactory() *clientcmd.Factory {
+func getFactory(connection Connection) (*clientcmd.Factory, error) {
+	if len(connection.Kubeconfig) > 0 || len(connection.Context) > 0 {
+		return getFactoryFromKubeconfig(connection)
+	}
+
 	factory, err := getFactoryFromCluster()
 	if err != nil {
 		log.Printf("Seems like we are not running in an OpenShift environment (%s), falling back to building a std factory...", err)
 		factory = clientcmd.New(pflag.NewFlagSet("openshift-factory", pflag.ContinueOnError))
 	}
 
-	return factory
+	return factory, nil
+}
+
+// getFactoryFromKubeconfig returns an OpenShift's Factory
+// using the kubeconfig file and the context of the given connection
+func getFactoryFromKubeconfig(connection Connection) (*clientcmd.Factory, error) {
+	loadingRules := kclientcmd.NewDefaultClientConfigLoadingRules()
+	if len(connection.Kubeconfig) > 0 {
+		if _, err := os.Stat(connection.Kubeconfig); err != nil {
+			return nil, fmt.Errorf("Can't read the kubeconfig file %v: %v", connection.Kubeconfig, err)
+		}
+		loadingRules.ExplicitPath = connection.Kubeconfig
+	}
+
+	overrides := &kclientcmd.ConfigOverrides{
+		CurrentContext: connection.Context,
+	}
+
+	config := kclientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	return clientcmd.NewFactory(config), nil
 }
 
 // getFactoryFromCluster returns an OpenShift's Factory
@@ -305,3 +450,35 @@ func getFactoryFromCluster() (*clientcmd.Factory, error) {
 	factory := clientcmd.NewFactory(config)
 	return factory, nil
 }
+
+// getFactoryForToken returns an OpenShift's Factory
+// that connects to the same API Server than the given factory,
+// but authenticates with the given token.
+func getFactoryForToken(factory *clientcmd.Factory, token string) (*clientcmd.Factory, error) {
+	clientConfig, err := factory.OpenShiftClientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := &kclientcmd.ConfigOverrides{
+		ClusterInfo: kclientcmdapi.Cluster{
+			Server:                clientConfig.Host,
+			APIVersion:            clientConfig.Version,
+			InsecureSkipTLSVerify: clientConfig.Insecure,
+		},
+		AuthInfo: kclientcmdapi.AuthInfo{
+			Token: token,
+		},
+		Context: kclientcmdapi.Context{},
+	}
+
+	// the CA can't be used with the insecure flag
+	if !clientConfig.Insecure {
+		overrides.ClusterInfo.CertificateAuthority = clientConfig.TLSClientConfig.CAFile
+		overrides.ClusterInfo.CertificateAuthorityData = clientConfig.TLSClientConfig.CAData
+	}
+
+	config := kclientcmd.NewDefaultClientConfig(*kclientcmdapi.NewConfig(), overrides)
+
+	return clientcmd.NewFactory(config), nil
+}
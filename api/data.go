@@ -19,6 +19,7 @@ type Data struct {
 	Projects               []projectapi.Project
 	Routes                 []routeapi.Route
 	Services               []kapi.Service
+	Endpoints              []kapi.Endpoints
 	Pods                   []kapi.Pod
 	Containers             []kapi.Container
 	ImageStreams           []imageapi.ImageStream
@@ -27,6 +28,18 @@ type Data struct {
 	DeploymentConfigs      []deployapi.DeploymentConfig
 	ReplicationControllers []kapi.ReplicationController
 	Events                 []kapi.Event
+
+	// Grouping describes how the objects are grouped in applications and secondary dimensions
+	Grouping Grouping `json:"-"`
+
+	// RoutePolicy describes what is expected from the routes
+	RoutePolicy RoutePolicy `json:"-"`
+
+	// CleanupPolicy describes when the resources are considered as unused
+	CleanupPolicy CleanupPolicy `json:"-"`
+
+	// index indexes the objects, once they are loaded
+	index *index
 }
 
 // Merge merges the given Data instances in this instance
@@ -41,6 +54,9 @@ func (d *Data) Merge(others ...*Data) {
 		if d.Services == nil && other.Services != nil {
 			d.Services = other.Services
 		}
+		if d.Endpoints == nil && other.Endpoints != nil {
+			d.Endpoints = other.Endpoints
+		}
 		if d.Pods == nil && other.Pods != nil {
 			d.Pods = other.Pods
 		}
@@ -81,6 +97,9 @@ func (d *Data) Set(resourceType ResourceType, resources []interface{}) error {
 	case ResourceTypeService:
 		return d.SetServices(resources)
 
+	case ResourceTypeEndpoints:
+		return d.SetEndpoints(resources)
+
 	case ResourceTypePod:
 		return d.SetPods(resources)
 
@@ -122,6 +141,23 @@ func (d *Data) SetProjects(projects []interface{}) error {
 	return nil
 }
 
+// RetainProjects removes from this Data instance all the projects
+// that are not in the given namespaces.
+func (d *Data) RetainProjects(namespaces []string) {
+	retained := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		retained[namespace] = true
+	}
+
+	projects := d.Projects
+	d.Projects = projects[:0]
+	for _, project := range projects {
+		if retained[project.Name] {
+			d.Projects = append(d.Projects, project)
+		}
+	}
+}
+
 func (d *Data) SetRoutes(routes []interface{}) error {
 	d.Routes = []routeapi.Route{}
 	for _, obj := range routes {
@@ -146,6 +182,18 @@ func (d *Data) SetServices(services []interface{}) error {
 	return nil
 }
 
+func (d *Data) SetEndpoints(endpoints []interface{}) error {
+	d.Endpoints = []kapi.Endpoints{}
+	for _, obj := range endpoints {
+		ep, ok := obj.(kapi.Endpoints)
+		if !ok {
+			return fmt.Errorf("Wrong type %T for endpoints!", endpoints)
+		}
+		d.Endpoints = append(d.Endpoints, ep)
+	}
+	return nil
+}
+
 func (d *Data) SetPods(pods []interface{}) error {
 	d.Pods = []kapi.Pod{}
 	for _, obj := range pods {
@@ -273,19 +321,17 @@ func (d *Data) SetEvents(events []interface{}) error {
 	return nil
 }
 
-// ExtractApplicationsFromDeploymentConfigs extracts all applications from the deploymentConfigs stored in this Data instance,
+// ExtractApplications extracts all applications from the objects stored in this Data instance,
 // and stores them in this Data instance.
-// The applications are in facts labels values for the label key "application"
-// We inspect the DC to retrieve the different labels values, because it is the object type that all applications are likely to have.
-func (d *Data) ExtractApplicationsFromDeploymentConfigs() {
-	applications := make(map[string]interface{})
-	for _, dc := range d.DeploymentConfigs {
-		if appName, labelExists := dc.Labels[ApplicationNameLabel]; labelExists {
-			if _, exists := applications[appName]; !exists {
-				applications[appName] = nil
-			}
+// The applications are the values of the application labels of the grouping (the "application" label by default),
+// on all the resource types: some applications only have BuildConfigs, or Routes.
+func (d *Data) ExtractApplications() {
+	applications := make(map[string]bool)
+	d.eachApplicationObject(func(resourceType ResourceType, i int, meta kapi.ObjectMeta) {
+		if appName := d.ApplicationOf(meta.Labels); len(appName) > 0 {
+			applications[appName] = true
 		}
-	}
+	})
 
 	d.Applications = []Application{}
 	for appName := range applications {
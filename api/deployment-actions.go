@@ -0,0 +1,103 @@
+package api
+
+import (
+	"fmt"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployscaler "github.com/openshift/origin/pkg/deploy/scaler"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// ScaleDeploymentConfig scales the latest deployment of the given DeploymentConfig
+// to the given number of replicas (the same way "oc scale" does).
+func (cw *ClientWrapper) ScaleDeploymentConfig(namespace string, name string, replicas uint) error {
+	client, kclient, err := cw.factory.Clients()
+	if err != nil {
+		return err
+	}
+
+	scaler := deployscaler.NewDeploymentConfigScaler(client, kclient)
+	return scaler.Scale(namespace, name, replicas, nil, nil, nil)
+}
+
+// RedeployDeploymentConfig triggers a new deployment of the given DeploymentConfig,
+// (the same way "oc deploy --latest" does).
+// It fails if the latest deployment is still in progress.
+func (cw *ClientWrapper) RedeployDeploymentConfig(namespace string, name string) error {
+	client, kclient, err := cw.factory.Clients()
+	if err != nil {
+		return err
+	}
+
+	dc, err := client.DeploymentConfigs(namespace).Get(name)
+	if err != nil {
+		return err
+	}
+
+	if dc.LatestVersion > 0 {
+		rc, err := kclient.ReplicationControllers(namespace).Get(deployutil.LatestDeploymentNameForConfig(dc))
+		if err != nil {
+			return err
+		}
+		if !IsDeploymentFinished(*rc) {
+			return fmt.Errorf("Deployment %s/%s is still in progress (%s)!", namespace, rc.Name, DeploymentStatusOf(*rc))
+		}
+	}
+
+	dc.LatestVersion++
+	_, err = client.DeploymentConfigs(namespace).Update(dc)
+	return err
+}
+
+// RollbackDeploymentConfig rolls back the given DeploymentConfig to the given (previous) deployment
+// (the same way "oc rollback" does): it triggers a new deployment with the template of the previous deployment.
+// The automatic image change triggers are disabled, so that the rollback is not immediately replaced
+// by a new deployment of the latest image.
+func (cw *ClientWrapper) RollbackDeploymentConfig(namespace string, name string, deploymentName string) error {
+	client, kclient, err := cw.factory.Clients()
+	if err != nil {
+		return err
+	}
+
+	rc, err := kclient.ReplicationControllers(namespace).Get(deploymentName)
+	if err != nil {
+		return err
+	}
+	if DeploymentConfigNameOf(*rc) != name {
+		return fmt.Errorf("Deployment %s/%s does not belong to the DeploymentConfig %s!", namespace, deploymentName, name)
+	}
+
+	current, err := client.DeploymentConfigs(namespace).Get(name)
+	if err != nil {
+		return err
+	}
+
+	dc, err := client.DeploymentConfigs(namespace).Rollback(&deployapi.DeploymentConfigRollback{
+		Spec: deployapi.DeploymentConfigRollbackSpec{
+			From: kapi.ObjectReference{
+				Kind:      "ReplicationController",
+				Namespace: namespace,
+				Name:      deploymentName,
+			},
+			IncludeTemplate: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, trigger := range dc.Triggers {
+		if trigger.Type == deployapi.DeploymentTriggerOnImageChange && trigger.ImageChangeParams != nil {
+			trigger.ImageChangeParams.Automatic = false
+		}
+	}
+
+	// depending on the version of the API Server, the rollback may already have bumped the version
+	if dc.LatestVersion <= current.LatestVersion {
+		dc.LatestVersion = current.LatestVersion + 1
+	}
+	_, err = client.DeploymentConfigs(namespace).Update(dc)
+	return err
+}
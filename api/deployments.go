@@ -0,0 +1,66 @@
+package api
+
+import (
+	"sort"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	deployutil "github.com/openshift/origin/pkg/deploy/util"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// DeploymentConfigNameOf returns the name of the DeploymentConfig that created the given deployment (ReplicationController),
+// or an empty string if it has not been created by a DeploymentConfig
+func DeploymentConfigNameOf(rc kapi.ReplicationController) string {
+	return deployutil.DeploymentConfigNameFor(&rc)
+}
+
+// DeploymentStatusOf returns the status of the given deployment (ReplicationController)
+func DeploymentStatusOf(rc kapi.ReplicationController) deployapi.DeploymentStatus {
+	return deployutil.DeploymentStatusFor(&rc)
+}
+
+// DeploymentVersionOf returns the version of the given deployment (ReplicationController),
+// or -1 if it has not been created by a DeploymentConfig
+func DeploymentVersionOf(rc kapi.ReplicationController) int {
+	return deployutil.DeploymentVersionFor(&rc)
+}
+
+// IsDeploymentFinished returns true if the given deployment (ReplicationController) is finished,
+// whatever its result
+func IsDeploymentFinished(rc kapi.ReplicationController) bool {
+	switch DeploymentStatusOf(rc) {
+	case deployapi.DeploymentStatusComplete, deployapi.DeploymentStatusFailed:
+		return true
+	}
+	return false
+}
+
+// DeploymentsOf returns the deployments (ReplicationControllers) created by the given DeploymentConfig,
+// the most recent first
+func (d *Data) DeploymentsOf(dc deployapi.DeploymentConfig) []kapi.ReplicationController {
+	deployments := []kapi.ReplicationController{}
+	for _, i := range d.indexed().deploymentsByDC[dc.Namespace+"/"+dc.Name] {
+		deployments = append(deployments, d.ReplicationControllers[i])
+	}
+	sort.Sort(deployutil.DeploymentsByLatestVersionDesc(deployments))
+	return deployments
+}
+
+// LatestDeploymentOf returns the latest deployment (ReplicationController) of the given DeploymentConfig,
+// or nil if it has not been deployed yet
+func (d *Data) LatestDeploymentOf(dc deployapi.DeploymentConfig) *kapi.ReplicationController {
+	name := deployutil.LatestDeploymentNameForConfig(&dc)
+	if i, found := d.indexed().find(ResourceTypeReplicationController, dc.Namespace, name); found {
+		return &d.ReplicationControllers[i]
+	}
+	return nil
+}
+
+// FindDeploymentConfig returns the DeploymentConfig with the given namespace and name
+func (d *Data) FindDeploymentConfig(namespace string, name string) (*deployapi.DeploymentConfig, bool) {
+	if i, found := d.indexed().find(ResourceTypeDeploymentConfig, namespace, name); found {
+		return &d.DeploymentConfigs[i], true
+	}
+	return nil, false
+}
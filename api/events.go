@@ -0,0 +1,25 @@
+package api
+
+import (
+	"strings"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// warningReasons are the reasons of the events that report a problem,
+// in addition to the ones starting with "failed"
+var warningReasons = map[string]bool{
+	"backoff":          true,
+	"unhealthy":        true,
+	"errimagepull":     true,
+	"imagepullbackoff": true,
+	"outofdisk":        true,
+	"nodenotready":     true,
+}
+
+// IsWarningEvent returns true if the given event reports a problem.
+// The events don't have a type (yet), so it is based on their reason.
+func IsWarningEvent(event kapi.Event) bool {
+	reason := strings.ToLower(event.Reason)
+	return strings.HasPrefix(reason, "failed") || warningReasons[reason]
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// Grouping describes how the objects are grouped: in applications, and in secondary dimensions
+// (such as the team or the environment), both from the labels of the objects.
+type Grouping struct {
+	// ApplicationLabels are the labels of the application, by order of preference (such as "app", then "application").
+	// The "application" label is used if it is empty.
+	ApplicationLabels []string
+
+	// Dimensions are the secondary dimensions, such as the team or the environment
+	Dimensions []Dimension
+
+	// Environments are the environments, in the order of the promotions of the images (such as "dev", then "prod").
+	// The DefaultEnvironments are used if it is empty.
+	Environments []string
+
+	// EnvironmentDimension is the name of the dimension that gives the environment of an object, such as "env".
+	// If it is empty (or if an object has no value for it), the environment is the suffix of the name of its project,
+	// such as "prod" for "myapp-prod".
+	EnvironmentDimension string
+}
+
+// DefaultEnvironments are the environments used if the grouping doesn't define them
+var DefaultEnvironments = []string{"dev", "test", "staging", "prod"}
+
+// Dimension is a secondary dimension of the objects, such as the team or the environment
+type Dimension struct {
+	// Name is the name of the dimension, such as "env"
+	Name string
+
+	// Title is the title of the dimension, such as "Environment" (or its name if empty)
+	Title string
+
+	// Labels are the labels of the dimension, by order of preference (such as "env", then "tier").
+	// If an object has none of them, the labels of its project are used.
+	Labels []string
+}
+
+// applicationLabels returns the labels of the application, by order of preference
+func (g Grouping) applicationLabels() []string {
+	if len(g.ApplicationLabels) == 0 {
+		return []string{ApplicationNameLabel}
+	}
+	return g.ApplicationLabels
+}
+
+// environments returns the environments, in the order of the promotions
+func (g Grouping) environments() []string {
+	if len(g.Environments) == 0 {
+		return DefaultEnvironments
+	}
+	return g.Environments
+}
+
+// Dimension returns the dimension with the given name
+func (g Grouping) Dimension(name string) (Dimension, bool) {
+	for _, dimension := range g.Dimensions {
+		if dimension.Name == name {
+			return dimension, true
+		}
+	}
+	return Dimension{}, false
+}
+
+// DisplayTitle returns the title of the dimension, or its name if it has no title
+func (dim Dimension) DisplayTitle() string {
+	if len(dim.Title) > 0 {
+		return dim.Title
+	}
+	return dim.Name
+}
+
+// firstLabelValue returns the value of the first of the given labels that is set, or an empty string
+func firstLabelValue(labels map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value := labels[key]; len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+// ApplicationOf returns the application of an object with the given labels,
+// from the first application label it has, or an empty string if it doesn't belong to an application
+func (d *Data) ApplicationOf(labels map[string]string) string {
+	return firstLabelValue(labels, d.Grouping.applicationLabels())
+}
+
+// DimensionOf returns the value of the given dimension for the object with the given metadata,
+// from its labels or from the labels of its project, or an empty string
+func (d *Data) DimensionOf(dimension Dimension, meta kapi.ObjectMeta) string {
+	if value := firstLabelValue(meta.Labels, dimension.Labels); len(value) > 0 {
+		return value
+	}
+	if i, found := d.indexed().find(ResourceTypeProject, "", meta.Namespace); found {
+		return firstLabelValue(d.Projects[i].Labels, dimension.Labels)
+	}
+	return ""
+}
+
+// EnvironmentOf returns the environment of the object with the given metadata,
+// from the environment dimension or from the suffix of the name of its project, or an empty string
+func (d *Data) EnvironmentOf(meta kapi.ObjectMeta) string {
+	if dimension, found := d.Grouping.Dimension(d.Grouping.EnvironmentDimension); found {
+		if value := d.DimensionOf(dimension, meta); len(value) > 0 {
+			return value
+		}
+	}
+	for _, environment := range d.Grouping.environments() {
+		if meta.Namespace == environment || strings.HasSuffix(meta.Namespace, "-"+environment) {
+			return environment
+		}
+	}
+	return ""
+}
+
+// sortEnvironments sorts the given environments in the order of the promotions,
+// followed by the unknown ones (by name)
+func (g Grouping) sortEnvironments(environments []string) {
+	order := make(map[string]int)
+	for i, environment := range g.environments() {
+		order[environment] = i + 1
+	}
+	sort.Sort(environmentsByOrder{environments: environments, order: order})
+}
+
+// environmentsByOrder sorts the environments by their order, and then by name
+type environmentsByOrder struct {
+	environments []string
+	order        map[string]int
+}
+
+func (e environmentsByOrder) Len() int { return len(e.environments) }
+func (e environmentsByOrder) Swap(i, j int) {
+	e.environments[i], e.environments[j] = e.environments[j], e.environments[i]
+}
+func (e environmentsByOrder) Less(i, j int) bool {
+	oi, oj := e.order[e.environments[i]], e.order[e.environments[j]]
+	switch {
+	case oi > 0 && oj > 0:
+		return oi < oj
+	case oi > 0 || oj > 0:
+		return oi > 0
+	default:
+		return e.environments[i] < e.environments[j]
+	}
+}
+
+// DimensionValues returns the values of the given dimension for the objects of this Data instance
+// that can belong to an application, sorted
+func (d *Data) DimensionValues(dimension Dimension) []string {
+	values := make(map[string]bool)
+	d.eachApplicationObject(func(resourceType ResourceType, i int, meta kapi.ObjectMeta) {
+		if value := d.DimensionOf(dimension, meta); len(value) > 0 {
+			values[value] = true
+		}
+	})
+
+	sorted := []string{}
+	for value := range values {
+		sorted = append(sorted, value)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// ForDimensionValue returns a new Data instance, with only the objects of this instance
+// that have the given value for the given dimension (the projects are kept)
+func (d *Data) ForDimensionValue(dimension Dimension, value string) *Data {
+	p := make(positions)
+	d.eachApplicationObject(func(resourceType ResourceType, i int, meta kapi.ObjectMeta) {
+		if d.DimensionOf(dimension, meta) == value {
+			p.add(resourceType, i)
+		}
+	})
+	for i := range d.Projects {
+		p.add(ResourceTypeProject, i)
+	}
+
+	return d.subset(p)
+}
+
+// eachApplicationObject calls the given function for each object of the resource types that can belong to an application,
+// with its resource type, position and metadata
+func (d *Data) eachApplicationObject(f func(resourceType ResourceType, i int, meta kapi.ObjectMeta)) {
+	for i, route := range d.Routes {
+		f(ResourceTypeRoute, i, route.ObjectMeta)
+	}
+	for i, service := range d.Services {
+		f(ResourceTypeService, i, service.ObjectMeta)
+	}
+	for i, endpoints := range d.Endpoints {
+		f(ResourceTypeEndpoints, i, endpoints.ObjectMeta)
+	}
+	for i, pod := range d.Pods {
+		f(ResourceTypePod, i, pod.ObjectMeta)
+	}
+	for i, is := range d.ImageStreams {
+		f(ResourceTypeImageStream, i, is.ObjectMeta)
+	}
+	for i, bc := range d.BuildConfigs {
+		f(ResourceTypeBuildConfig, i, bc.ObjectMeta)
+	}
+	for i, build := range d.Builds {
+		f(ResourceTypeBuild, i, build.ObjectMeta)
+	}
+	for i, dc := range d.DeploymentConfigs {
+		f(ResourceTypeDeploymentConfig, i, dc.ObjectMeta)
+	}
+	for i, rc := range d.ReplicationControllers {
+		f(ResourceTypeReplicationController, i, rc.ObjectMeta)
+	}
+}
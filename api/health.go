@@ -0,0 +1,86 @@
+package api
+
+import (
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// Health describes the possible health statuses of a DeploymentConfig or an application
+type Health string
+
+const (
+	// HealthHealthy means that all the desired replicas are ready
+	HealthHealthy Health = "healthy"
+
+	// HealthDegraded means that some (but not all) of the desired replicas are ready,
+	// or that the latest deployment failed
+	HealthDegraded Health = "degraded"
+
+	// HealthDown means that none of the desired replicas are ready
+	HealthDown Health = "down"
+
+	// HealthUnknown means that there is nothing deployed yet
+	HealthUnknown Health = "unknown"
+)
+
+// healthSeverity is used to find the worst health status
+var healthSeverity = map[Health]int{
+	HealthUnknown:  0,
+	HealthHealthy:  1,
+	HealthDegraded: 2,
+	HealthDown:     3,
+}
+
+// HealthOf returns the health of the given DeploymentConfig,
+// based on the ready pods of its latest deployment (or of all its deployments, while deploying)
+func (d *Data) HealthOf(dc deployapi.DeploymentConfig) Health {
+	rc := d.LatestDeploymentOf(dc)
+	if rc == nil {
+		return HealthUnknown
+	}
+
+	desired := rc.Spec.Replicas
+	deployments := []kapi.ReplicationController{*rc}
+	if !IsDeploymentFinished(*rc) {
+		// while deploying, the pods of the previous deployments are still serving requests
+		desired = dc.Template.ControllerTemplate.Replicas
+		deployments = d.DeploymentsOf(dc)
+	}
+
+	ready := 0
+	for _, deployment := range deployments {
+		for _, pod := range d.PodsOfDeployment(deployment) {
+			if IsPodReady(pod) {
+				ready++
+			}
+		}
+	}
+
+	switch {
+	case desired > 0 && ready == 0:
+		return HealthDown
+	case ready < desired:
+		return HealthDegraded
+	case DeploymentStatusOf(*rc) == deployapi.DeploymentStatusFailed:
+		return HealthDegraded
+	default:
+		return HealthHealthy
+	}
+}
+
+// ApplicationHealthOf returns the health of the given application in the given namespace
+// (or in all namespaces if the namespace is empty), which is the worst health of its DeploymentConfigs
+func (d *Data) ApplicationHealthOf(application string, namespace string) Health {
+	health := HealthUnknown
+	for _, i := range d.indexed().byApplication[application][ResourceTypeDeploymentConfig] {
+		dc := d.DeploymentConfigs[i]
+		if len(namespace) > 0 && dc.Namespace != namespace {
+			continue
+		}
+		if dcHealth := d.HealthOf(dc); healthSeverity[dcHealth] > healthSeverity[health] {
+			health = dcHealth
+		}
+	}
+	return health
+}
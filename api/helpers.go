@@ -2,120 +2,289 @@ package api
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	buildapi "github.com/openshift/origin/pkg/build/api"
-	deployapi "github.com/openshift/origin/pkg/deploy/api"
-	imageapi "github.com/openshift/origin/pkg/image/api"
+	projectapi "github.com/openshift/origin/pkg/project/api"
 	routeapi "github.com/openshift/origin/pkg/route/api"
 
 	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
 )
 
-// FilterByApplication returns all objects that belongs to the given application
+// FilterByApplication returns all objects that belongs to the given application, from the default "application" label.
+// The FilterByApplication method of a Grouping uses its application labels instead.
 func FilterByApplication(objects interface{}, application string) ([]interface{}, error) {
-	return FilterByLabelValue(objects, ApplicationNameLabel, application)
+	return Grouping{}.FilterByApplication(objects, application)
 }
 
-// FilterByLabelValue returns all objects that have the given key/value label
+// FilterByApplication returns all objects that belongs to the given application, from the first application label
+// of this grouping that they have (as ApplicationOf).
+// It scans all the given objects: the ForApplication method of an indexed Data instance is faster.
+func (g Grouping) FilterByApplication(objects interface{}, application string) ([]interface{}, error) {
+	applicationLabels := g.applicationLabels()
+	return filterObjects(objects, func(object interface{}, accessor meta.Interface) bool {
+		return firstLabelValue(accessor.Labels(), applicationLabels) == application
+	})
+}
+
+// FilterByLabelValue returns all objects that have the given key/value label.
+// It scans all the given objects: the ForLabelValue method of an indexed Data instance is faster.
 func FilterByLabelValue(objects interface{}, labelKey string, labelValue string) ([]interface{}, error) {
-	results := []interface{}{}
+	return filterObjects(objects, func(object interface{}, accessor meta.Interface) bool {
+		value, found := accessor.Labels()[labelKey]
+		return found && value == labelValue
+	})
+}
 
-	switch objects.(type) {
+// FilterByNamespace returns all objects that belongs to the given namespace (project).
+// It scans all the given objects: the ForNamespace method of an indexed Data instance is faster.
+func FilterByNamespace(objects interface{}, namespace string) ([]interface{}, error) {
+	return filterObjects(objects, func(object interface{}, accessor meta.Interface) bool {
+		return accessor.Namespace() == namespace
+	})
+}
 
-	case []routeapi.Route:
-		for _, route := range objects.([]routeapi.Route) {
-			if hasLabelValue(route.ObjectMeta, labelKey, labelValue) {
-				results = append(results, route)
-			}
-		}
+// FilterBySelector returns all objects that match the given label selector, such as "env in (prod,staging),!canary"
+func FilterBySelector(objects interface{}, labelSelector string) ([]interface{}, error) {
+	selector, err := ParseObjectSelector(labelSelector, "")
+	if err != nil {
+		return nil, err
+	}
+	return filterObjects(objects, selector.matches)
+}
 
-	case []kapi.Service:
-		for _, service := range objects.([]kapi.Service) {
-			if hasLabelValue(service.ObjectMeta, labelKey, labelValue) {
-				results = append(results, service)
-			}
-		}
+// FilterByFields returns all objects that match the given field selector, such as "status.phase=Running"
+// (see FieldsOf for the supported fields)
+func FilterByFields(objects interface{}, fieldSelector string) ([]interface{}, error) {
+	selector, err := ParseObjectSelector("", fieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	return filterObjects(objects, selector.matches)
+}
 
-	case []imageapi.ImageStream:
-		for _, is := range objects.([]imageapi.ImageStream) {
-			if hasLabelValue(is.ObjectMeta, labelKey, labelValue) {
-				results = append(results, is)
-			}
-		}
+// ObjectSelector selects the API objects by their labels and their fields
+type ObjectSelector struct {
+	Labels labels.Selector
+	Fields fields.Selector
+}
 
-	case []buildapi.BuildConfig:
-		for _, bc := range objects.([]buildapi.BuildConfig) {
-			if hasLabelValue(bc.ObjectMeta, labelKey, labelValue) {
-				results = append(results, bc)
+// ParseObjectSelector parses the given label selector (such as "env in (prod,staging),!canary")
+// and field selector (such as "status.phase=Running,metadata.namespace!=sandbox").
+// Both can be empty, to select all the objects.
+func ParseObjectSelector(labelSelector string, fieldSelector string) (ObjectSelector, error) {
+	labelsSelector, err := ParseLabelSelector(labelSelector)
+	if err != nil {
+		return ObjectSelector{}, err
+	}
+	fieldsSelector, err := fields.ParseSelector(fieldSelector)
+	if err != nil {
+		return ObjectSelector{}, fmt.Errorf("Invalid field selector %q: %v", fieldSelector, err)
+	}
+	return ObjectSelector{
+		Labels: labelsSelector,
+		Fields: fieldsSelector,
+	}, nil
+}
+
+// ParseLabelSelector parses the given label selector, such as "env in (prod,staging),!canary".
+// In addition to the syntax supported by the labels package, a "!key" requirement selects the objects without the key.
+func ParseLabelSelector(selector string) (labels.Selector, error) {
+	absentKeys := []string{}
+	requirements := []string{}
+	for _, requirement := range splitRequirements(selector) {
+		requirement = strings.TrimSpace(requirement)
+		if strings.HasPrefix(requirement, "!") && !strings.HasPrefix(requirement, "!=") {
+			key := strings.TrimSpace(requirement[1:])
+			// a single key is an "exists" requirement, which validates the key
+			if _, err := labels.Parse(key); err != nil || len(key) == 0 {
+				return nil, fmt.Errorf("Invalid label selector %q: invalid key in %q", selector, requirement)
 			}
+			absentKeys = append(absentKeys, key)
+			continue
 		}
+		requirements = append(requirements, requirement)
+	}
 
-	case []deployapi.DeploymentConfig:
-		for _, dc := range objects.([]deployapi.DeploymentConfig) {
-			if hasLabelValue(dc.ObjectMeta, labelKey, labelValue) {
-				results = append(results, dc)
+	parsed, err := labels.Parse(strings.Join(requirements, ","))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid label selector %q: %v", selector, err)
+	}
+	if len(absentKeys) == 0 {
+		return parsed, nil
+	}
+	return &labelSelector{Selector: parsed, absentKeys: absentKeys}, nil
+}
+
+// splitRequirements splits the given label selector on the commas that separate its requirements,
+// but not on the commas between the parenthesis of a set of values
+func splitRequirements(selector string) []string {
+	requirements := []string{}
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selector[start:i])
+				start = i + 1
 			}
 		}
+	}
+	if rest := selector[start:]; len(strings.TrimSpace(rest)) > 0 || len(requirements) > 0 {
+		requirements = append(requirements, rest)
+	}
+	return requirements
+}
 
-	default:
-		return nil, fmt.Errorf("Unsupported transformation of type %T", objects)
+// labelSelector is a label selector with "!key" requirements, which are not supported by the labels package
+type labelSelector struct {
+	labels.Selector
+
+	// absentKeys are the keys that the labels must not have
+	absentKeys []string
+}
+
+// Matches returns true if the given labels don't have the absent keys, and match the other requirements
+func (s *labelSelector) Matches(l labels.Labels) bool {
+	for _, key := range s.absentKeys {
+		if l.Has(key) {
+			return false
+		}
 	}
+	return s.Selector.Matches(l)
+}
 
-	return results, nil
+// Empty returns false, because there is at least one absent key
+func (s *labelSelector) Empty() bool {
+	return false
 }
 
-// FilterByNamespace returns all objects that belongs to the given namespace (project)
-func FilterByNamespace(objects interface{}, namespace string) ([]interface{}, error) {
-	results := []interface{}{}
+// String returns the selector, with its absent keys
+func (s *labelSelector) String() string {
+	requirements := []string{}
+	if !s.Selector.Empty() {
+		requirements = append(requirements, s.Selector.String())
+	}
+	for _, key := range s.absentKeys {
+		requirements = append(requirements, "!"+key)
+	}
+	return strings.Join(requirements, ",")
+}
 
-	switch objects.(type) {
+// IsEmpty returns true if the selector selects all the objects
+func (s ObjectSelector) IsEmpty() bool {
+	return s.Labels.Empty() && s.Fields.Empty()
+}
 
-	case []routeapi.Route:
-		for _, route := range objects.([]routeapi.Route) {
-			if route.Namespace == namespace {
-				results = append(results, route)
-			}
-		}
+// matches returns true if the given API object (a pointer), with the given metadata, matches the selector
+func (s ObjectSelector) matches(object interface{}, accessor meta.Interface) bool {
+	if !s.Labels.Matches(labels.Set(accessor.Labels())) {
+		return false
+	}
+	return s.Fields.Empty() || s.Fields.Matches(FieldsOf(object))
+}
 
-	case []kapi.Service:
-		for _, service := range objects.([]kapi.Service) {
-			if service.Namespace == namespace {
-				results = append(results, service)
-			}
-		}
+// FieldsOf returns the fields of the given API object (a pointer) that can be used in a field selector:
+// - metadata.name and metadata.namespace, for all the objects
+// - status.phase, for the projects, pods, builds and deployments (ReplicationControllers)
+// - spec.host and spec.to.name (the service), for the routes
+// - spec.nodeName, for the pods
+// - reason, involvedObject.kind and involvedObject.name, for the events
+func FieldsOf(object interface{}) fields.Set {
+	set := fields.Set{}
+	if accessor, err := meta.Accessor(object); err == nil {
+		set["metadata.name"] = accessor.Name()
+		set["metadata.namespace"] = accessor.Namespace()
+	}
 
-	case []imageapi.ImageStream:
-		for _, is := range objects.([]imageapi.ImageStream) {
-			if is.Namespace == namespace {
-				results = append(results, is)
-			}
-		}
+	switch o := object.(type) {
+	case *projectapi.Project:
+		set["status.phase"] = string(o.Status.Phase)
+	case *routeapi.Route:
+		set["spec.host"] = o.Host
+		set["spec.to.name"] = o.ServiceName
+	case *kapi.Pod:
+		set["status.phase"] = string(o.Status.Phase)
+		set["spec.nodeName"] = o.Spec.NodeName
+	case *buildapi.Build:
+		set["status.phase"] = string(o.Status.Phase)
+	case *kapi.ReplicationController:
+		set["status.phase"] = string(DeploymentStatusOf(*o))
+	case *kapi.Event:
+		set["reason"] = o.Reason
+		set["involvedObject.kind"] = o.InvolvedObject.Kind
+		set["involvedObject.name"] = o.InvolvedObject.Name
+	}
+	return set
+}
 
-	case []buildapi.BuildConfig:
-		for _, bc := range objects.([]buildapi.BuildConfig) {
-			if bc.Namespace == namespace {
-				results = append(results, bc)
-			}
-		}
+// filterObjects returns the objects of the given slice of API objects (such as []kapi.Pod)
+// that are accepted by the given function
+func filterObjects(objects interface{}, accept func(object interface{}, accessor meta.Interface) bool) ([]interface{}, error) {
+	matching, err := matchingPositions(objects, accept)
+	if err != nil {
+		return nil, err
+	}
 
-	case []deployapi.DeploymentConfig:
-		for _, dc := range objects.([]deployapi.DeploymentConfig) {
-			if dc.Namespace == namespace {
-				results = append(results, dc)
-			}
-		}
+	value := reflect.ValueOf(objects)
+	results := []interface{}{}
+	for _, i := range matching {
+		results = append(results, value.Index(i).Interface())
+	}
+	return results, nil
+}
 
-	default:
+// matchingPositions returns the positions of the objects of the given slice of API objects (such as []kapi.Pod)
+// that are accepted by the given function, which is called with a pointer to each object and its metadata
+func matchingPositions(objects interface{}, accept func(object interface{}, accessor meta.Interface) bool) ([]int, error) {
+	value := reflect.ValueOf(objects)
+	if value.Kind() != reflect.Slice {
 		return nil, fmt.Errorf("Unsupported transformation of type %T", objects)
 	}
 
-	return results, nil
+	matching := []int{}
+	for i := 0; i < value.Len(); i++ {
+		object := value.Index(i).Addr().Interface()
+		accessor, err := meta.Accessor(object)
+		if err != nil {
+			return nil, fmt.Errorf("Unsupported transformation of type %T: %v", objects, err)
+		}
+		if accept(object, accessor) {
+			matching = append(matching, i)
+		}
+	}
+	return matching, nil
 }
 
-// hasLabelValue returns true if the given object has the given key/value label
-func hasLabelValue(meta kapi.ObjectMeta, labelKey string, labelValue string) bool {
-	if value, found := meta.Labels[labelKey]; found && value == labelValue {
-		return true
+// Select returns a new Data instance, with only the objects of this instance that match the given selector
+func (d *Data) Select(selector ObjectSelector) *Data {
+	if selector.IsEmpty() {
+		return d
 	}
-	return false
+
+	p := make(positions)
+	add := func(resourceType ResourceType, objects interface{}) {
+		// the objects of the Data instance are always API objects, so there can't be any errors
+		p[resourceType], _ = matchingPositions(objects, selector.matches)
+	}
+	add(ResourceTypeProject, d.Projects)
+	add(ResourceTypeRoute, d.Routes)
+	add(ResourceTypeService, d.Services)
+	add(ResourceTypeEndpoints, d.Endpoints)
+	add(ResourceTypePod, d.Pods)
+	add(ResourceTypeImageStream, d.ImageStreams)
+	add(ResourceTypeBuildConfig, d.BuildConfigs)
+	add(ResourceTypeBuild, d.Builds)
+	add(ResourceTypeDeploymentConfig, d.DeploymentConfigs)
+	add(ResourceTypeReplicationController, d.ReplicationControllers)
+	add(ResourceTypeEvent, d.Events)
+	return d.subset(p)
 }
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// ContainerImage is a container image used by the pods and DeploymentConfigs, with the context of its use
+type ContainerImage struct {
+	// Image is the reference of the image, as used in the containers,
+	// such as "172.30.1.1:5000/myproject/myapp@sha256:1234..." or "nginx:1.9"
+	Image string
+
+	// Registry, Repository and Tag are the parts of the reference (the Docker Hub registry is "docker.io")
+	Registry   string
+	Repository string
+	Tag        string
+
+	// Digest is the digest of the image, from its reference or from the ImageStreams, if it is known
+	Digest string
+
+	// ImageStream is the ImageStream the image comes from ("namespace/name"), and its tag, if any
+	ImageStream    string
+	ImageStreamTag string
+
+	// Created is the first time the image has been tagged in an ImageStream, if it is known
+	Created time.Time
+
+	// NewerTag is the tag of the ImageStream with a more recent image, if any.
+	// It is the tag of the image if it has moved to a new image, or else the most recent tag.
+	NewerTag string
+
+	// Latest is true if the image is referenced by the "latest" tag (explicitly or not)
+	Latest bool
+
+	// External is true if the image is pulled from a registry outside of the cluster
+	External bool
+
+	// Projects and Applications are the ones of the pods and DeploymentConfigs using the image, sorted
+	Projects     []string
+	Applications []string
+
+	// Pods is the number of pods using the image,
+	// and DeploymentConfigs the DeploymentConfigs using it ("namespace/name"), sorted
+	Pods              int
+	DeploymentConfigs []string
+}
+
+// ShortDigest returns the first characters of the digest of the image (without the algorithm), or an empty string
+func (i *ContainerImage) ShortDigest() string {
+	return shortDigest(i.Digest)
+}
+
+// Age returns how old the image is, or 0 if it is not known
+func (i *ContainerImage) Age() time.Duration {
+	if i.Created.IsZero() {
+		return 0
+	}
+	return time.Since(i.Created)
+}
+
+// ContainerImages returns the images used by the containers of the pods and DeploymentConfigs, sorted by reference.
+// They are matched with the history of the ImageStream tags, to find where they come from, and how old they are.
+func (d *Data) ContainerImages() []ContainerImage {
+	history := newImageHistory(d)
+	images := make(map[string]*ContainerImage)
+	projects := make(map[string]map[string]bool)
+	applications := make(map[string]map[string]bool)
+
+	use := func(container kapi.Container, meta kapi.ObjectMeta) *ContainerImage {
+		image, found := images[container.Image]
+		if !found {
+			image = history.containerImage(container.Image)
+			images[container.Image] = image
+			projects[container.Image] = make(map[string]bool)
+			applications[container.Image] = make(map[string]bool)
+		}
+		projects[container.Image][meta.Namespace] = true
+		if application := d.ApplicationOf(meta.Labels); len(application) > 0 {
+			applications[container.Image][application] = true
+		}
+		return image
+	}
+
+	for _, pod := range d.Pods {
+		for _, container := range pod.Spec.Containers {
+			use(container, pod.ObjectMeta).Pods++
+		}
+	}
+	for _, dc := range d.DeploymentConfigs {
+		if dc.Template.ControllerTemplate.Template == nil {
+			continue
+		}
+		for _, container := range dc.Template.ControllerTemplate.Template.Spec.Containers {
+			image := use(container, dc.ObjectMeta)
+			image.DeploymentConfigs = append(image.DeploymentConfigs, dc.Namespace+"/"+dc.Name)
+		}
+	}
+
+	references := []string{}
+	for reference := range images {
+		references = append(references, reference)
+	}
+	sort.Strings(references)
+
+	result := []ContainerImage{}
+	for _, reference := range references {
+		image := images[reference]
+		image.Projects = sortedKeys(projects[reference])
+		image.Applications = sortedKeys(applications[reference])
+		sort.Strings(image.DeploymentConfigs)
+		result = append(result, *image)
+	}
+	return result
+}
+
+// containerImage returns the image with the given reference, resolved with the history of the ImageStream tags
+func (h *imageHistory) containerImage(reference string) *ContainerImage {
+	image := &ContainerImage{
+		Image: reference,
+	}
+	ref, err := imageapi.ParseDockerImageReference(reference)
+	if err == nil {
+		image.Registry = ref.Registry
+		if len(image.Registry) == 0 {
+			image.Registry = "docker.io"
+		}
+		image.Repository = ref.Name
+		if len(ref.Namespace) > 0 {
+			image.Repository = ref.Namespace + "/" + ref.Name
+		}
+		image.Tag = ref.Tag
+		image.Digest = ref.ID
+		image.Latest = ref.Tag == imageapi.DefaultImageTag || (len(ref.Tag) == 0 && len(ref.ID) == 0)
+		image.External = !h.registries[ref.Registry]
+	}
+	if len(image.Digest) == 0 {
+		image.Digest = h.digests[reference]
+	}
+
+	pushes := h.pushes[image.Digest]
+	if len(image.Digest) == 0 || len(pushes) == 0 {
+		return image
+	}
+
+	// the ImageStream tag of the reference, or else a tag that currently references the image,
+	// or else the first ImageStream tag of the image
+	origin := pushes[0]
+	for _, push := range pushes {
+		if ref.Namespace == push.namespace && ref.Name == push.imageStream && ref.Tag == push.tag {
+			origin = push
+			break
+		}
+		if h.isCurrent(push, image.Digest) && !h.isCurrent(origin, image.Digest) {
+			origin = push
+		}
+	}
+	image.ImageStream = origin.namespace + "/" + origin.imageStream
+	image.ImageStreamTag = origin.tag
+	image.Created = pushes[0].created
+	image.NewerTag = h.newerTagOf(image, origin)
+	return image
+}
+
+// isCurrent returns true if the tag of the given push currently references the image with the given digest
+func (h *imageHistory) isCurrent(push imagePush, digest string) bool {
+	for _, current := range h.current[push.namespace+"/"+push.imageStream] {
+		if current.tag == push.tag {
+			return current.image == digest
+		}
+	}
+	return false
+}
+
+// newerTagOf returns the tag of the ImageStream of the given image that references a more recent image
+// (by the first time the images have been tagged): the tag of the image if it has moved to a more recent image,
+// or else the tag with the most recent image, or an empty string
+func (h *imageHistory) newerTagOf(image *ContainerImage, origin imagePush) string {
+	newer := ""
+	var newest time.Time
+	for _, current := range h.current[image.ImageStream] {
+		created := h.pushes[current.image][0].created
+		if current.image == image.Digest || !created.After(image.Created) {
+			continue
+		}
+		if current.tag == origin.tag {
+			return current.tag
+		}
+		if created.After(newest) {
+			newer, newest = current.tag, created
+		}
+	}
+	return newer
+}
+
+// shortDigest returns the first characters of the given digest, without its algorithm (such as "sha256:")
+func shortDigest(digest string) string {
+	digest = digest[strings.Index(digest, ":")+1:]
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
+
+// sortedKeys returns the keys of the given set, sorted
+func sortedKeys(set map[string]bool) []string {
+	keys := []string{}
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// imageHistory is the history of the ImageStream tags, by image digest
+type imageHistory struct {
+	data *Data
+
+	// digests are the digests of the images, by pull spec (such as "172.30.1.1:5000/myapp-dev/myapp:latest")
+	digests map[string]string
+
+	// tags are the tags that currently point to each image, such as "myapp-dev/myapp:latest"
+	tags map[string][]string
+
+	// pushes are the tag events of each image, the oldest first
+	pushes map[string][]imagePush
+
+	// current are the current images of the tags, per ImageStream ("namespace/name")
+	current map[string][]currentTag
+
+	// registries are the hosts of the registries of the ImageStreams (the registries of the cluster)
+	registries map[string]bool
+}
+
+// currentTag is the image currently referenced by a tag of an ImageStream
+type currentTag struct {
+	tag     string
+	image   string
+	created time.Time
+}
+
+// imagePush is a tag event of an image: the time it has been tagged in an ImageStream
+type imagePush struct {
+	namespace   string
+	imageStream string
+	tag         string
+	created     time.Time
+}
+
+// newImageHistory indexes the tag events of the ImageStreams of the given data
+func newImageHistory(d *Data) *imageHistory {
+	history := &imageHistory{
+		data:       d,
+		digests:    make(map[string]string),
+		tags:       make(map[string][]string),
+		pushes:     make(map[string][]imagePush),
+		current:    make(map[string][]currentTag),
+		registries: make(map[string]bool),
+	}
+	for _, is := range d.ImageStreams {
+		if ref, err := imageapi.ParseDockerImageReference(is.Status.DockerImageRepository); err == nil && len(ref.Registry) > 0 {
+			history.registries[ref.Registry] = true
+		}
+		for _, tag := range sortedTagsOf(is) {
+			for i, event := range is.Status.Tags[tag].Items {
+				if len(event.Image) == 0 {
+					continue
+				}
+				if i == 0 {
+					history.current[is.Namespace+"/"+is.Name] = append(history.current[is.Namespace+"/"+is.Name], currentTag{
+						tag:     tag,
+						image:   event.Image,
+						created: event.Created.Time,
+					})
+					history.tags[event.Image] = append(history.tags[event.Image], is.Namespace+"/"+is.Name+":"+tag)
+					if len(is.Status.DockerImageRepository) > 0 {
+						history.digests[is.Status.DockerImageRepository+":"+tag] = event.Image
+						if tag == imageapi.DefaultImageTag {
+							history.digests[is.Status.DockerImageRepository] = event.Image
+						}
+					}
+				}
+				if len(event.DockerImageReference) > 0 {
+					history.digests[event.DockerImageReference] = event.Image
+				}
+				history.pushes[event.Image] = append(history.pushes[event.Image], imagePush{
+					namespace:   is.Namespace,
+					imageStream: is.Name,
+					tag:         tag,
+					created:     event.Created.Time,
+				})
+			}
+		}
+	}
+	for _, pushes := range history.pushes {
+		sort.Sort(imagePushesByCreation(pushes))
+	}
+	return history
+}
+
+// buildOf returns the build that produced the image with the given digest, or nil if it has not been found:
+// the latest complete build that started before the first time the image has been tagged in its output ImageStream tag
+func (h *imageHistory) buildOf(digest string) *buildapi.Build {
+	for _, push := range h.pushes[digest] {
+		var found *buildapi.Build
+		for i, build := range h.data.Builds {
+			if build.Status.Phase != buildapi.BuildPhaseComplete || build.Status.StartTimestamp == nil {
+				continue
+			}
+			// the API timestamps have a precision of a second
+			if build.Status.StartTimestamp.Unix() > push.created.Unix() || !isOutputOf(build, push) {
+				continue
+			}
+			if found == nil || build.Status.StartTimestamp.Time.After(found.Status.StartTimestamp.Time) {
+				found = &h.data.Builds[i]
+			}
+		}
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// isOutputOf returns true if the given push is on the output ImageStream tag of the given build
+func isOutputOf(build buildapi.Build, push imagePush) bool {
+	to := build.Spec.Output.To
+	if to == nil {
+		return false
+	}
+	namespace := to.Namespace
+	if len(namespace) == 0 {
+		namespace = build.Namespace
+	}
+	if namespace != push.namespace {
+		return false
+	}
+	switch to.Kind {
+	case "ImageStreamTag":
+		return to.Name == push.imageStream+":"+push.tag
+	case "ImageStream", "ImageRepository":
+		return to.Name == push.imageStream && push.tag == imageapi.DefaultImageTag
+	}
+	return false
+}
+
+// sortedTagsOf returns the tags of the given ImageStream, sorted by name
+func sortedTagsOf(is imageapi.ImageStream) []string {
+	tags := []string{}
+	for tag := range is.Status.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// imagePushesByCreation sorts the pushes from the oldest one
+type imagePushesByCreation []imagePush
+
+func (p imagePushesByCreation) Len() int           { return len(p) }
+func (p imagePushesByCreation) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p imagePushesByCreation) Less(i, j int) bool { return p[i].created.Before(p[j].created) }
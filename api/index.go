@@ -0,0 +1,229 @@
+package api
+
+import (
+	"sort"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// positions are the positions of some objects in the slices of a Data instance, per resource type
+type positions map[ResourceType][]int
+
+// add adds the given position of an object of the given resource type
+func (p positions) add(resourceType ResourceType, i int) {
+	p[resourceType] = append(p[resourceType], i)
+}
+
+// index indexes the objects of a Data instance, so that they can be found without scanning all the objects
+type index struct {
+	// byNamespace are the positions of the objects of each namespace
+	byNamespace map[string]positions
+
+	// byLabel are the positions of the objects with each label, by "key=value"
+	byLabel map[string]positions
+
+	// byApplication are the positions of the objects of each application (from the grouping of the data)
+	byApplication map[string]positions
+
+	// byName is the position of each object, by "namespace/name", per resource type
+	byName map[ResourceType]map[string]int
+
+	// deploymentsByDC, podsByRC and buildsByBC are the positions of the objects, by "namespace/name" of their owner:
+	// the deployments (ReplicationControllers) of the DeploymentConfigs, the pods of the deployments,
+	// and the builds of the BuildConfigs
+	deploymentsByDC map[string][]int
+	podsByRC        map[string][]int
+	buildsByBC      map[string][]int
+}
+
+// BuildIndex indexes the objects of this Data instance by namespace, by label, by application,
+// by name and by owner, so that the lookups (ForApplication, BuildsOf, HealthOf, ...) don't have to scan all the objects.
+// It is called once the data are loaded, and must be called again if they are modified.
+func (d *Data) BuildIndex() {
+	d.index = newIndex(d)
+}
+
+// indexed returns the index of this Data instance,
+// or a new one if it has not been indexed (such as the data read from a file)
+func (d *Data) indexed() *index {
+	if d.index != nil {
+		return d.index
+	}
+	return newIndex(d)
+}
+
+// newIndex indexes the objects of the given Data instance
+func newIndex(d *Data) *index {
+	idx := &index{
+		byNamespace:     make(map[string]positions),
+		byLabel:         make(map[string]positions),
+		byApplication:   make(map[string]positions),
+		byName:          make(map[ResourceType]map[string]int),
+		deploymentsByDC: make(map[string][]int),
+		podsByRC:        make(map[string][]int),
+		buildsByBC:      make(map[string][]int),
+	}
+
+	for i, project := range d.Projects {
+		idx.add(ResourceTypeProject, i, project.ObjectMeta)
+	}
+	for i, route := range d.Routes {
+		idx.add(ResourceTypeRoute, i, route.ObjectMeta)
+	}
+	for i, service := range d.Services {
+		idx.add(ResourceTypeService, i, service.ObjectMeta)
+	}
+	for i, endpoints := range d.Endpoints {
+		idx.add(ResourceTypeEndpoints, i, endpoints.ObjectMeta)
+	}
+	for i, pod := range d.Pods {
+		idx.add(ResourceTypePod, i, pod.ObjectMeta)
+		if rc, found := pod.Labels[deployapi.DeploymentLabel]; found {
+			key := pod.Namespace + "/" + rc
+			idx.podsByRC[key] = append(idx.podsByRC[key], i)
+		}
+	}
+	for i, is := range d.ImageStreams {
+		idx.add(ResourceTypeImageStream, i, is.ObjectMeta)
+	}
+	for i, bc := range d.BuildConfigs {
+		idx.add(ResourceTypeBuildConfig, i, bc.ObjectMeta)
+	}
+	for i, build := range d.Builds {
+		idx.add(ResourceTypeBuild, i, build.ObjectMeta)
+		if bc := BuildConfigNameOf(build); len(bc) > 0 {
+			key := build.Namespace + "/" + bc
+			idx.buildsByBC[key] = append(idx.buildsByBC[key], i)
+		}
+	}
+	for i, dc := range d.DeploymentConfigs {
+		idx.add(ResourceTypeDeploymentConfig, i, dc.ObjectMeta)
+	}
+	for i, rc := range d.ReplicationControllers {
+		idx.add(ResourceTypeReplicationController, i, rc.ObjectMeta)
+		if dc := DeploymentConfigNameOf(rc); len(dc) > 0 {
+			key := rc.Namespace + "/" + dc
+			idx.deploymentsByDC[key] = append(idx.deploymentsByDC[key], i)
+		}
+	}
+	for i, event := range d.Events {
+		idx.add(ResourceTypeEvent, i, event.ObjectMeta)
+	}
+	d.eachApplicationObject(func(resourceType ResourceType, i int, meta kapi.ObjectMeta) {
+		if application := d.ApplicationOf(meta.Labels); len(application) > 0 {
+			if _, found := idx.byApplication[application]; !found {
+				idx.byApplication[application] = make(positions)
+			}
+			idx.byApplication[application].add(resourceType, i)
+		}
+	})
+	return idx
+}
+
+// add indexes the object of the given resource type at the given position, with the given metadata
+func (idx *index) add(resourceType ResourceType, i int, meta kapi.ObjectMeta) {
+	if _, found := idx.byNamespace[meta.Namespace]; !found {
+		idx.byNamespace[meta.Namespace] = make(positions)
+	}
+	idx.byNamespace[meta.Namespace].add(resourceType, i)
+
+	for key, value := range meta.Labels {
+		label := key + "=" + value
+		if _, found := idx.byLabel[label]; !found {
+			idx.byLabel[label] = make(positions)
+		}
+		idx.byLabel[label].add(resourceType, i)
+	}
+
+	if _, found := idx.byName[resourceType]; !found {
+		idx.byName[resourceType] = make(map[string]int)
+	}
+	idx.byName[resourceType][meta.Namespace+"/"+meta.Name] = i
+}
+
+// find returns the position of the object of the given resource type, namespace and name
+func (idx *index) find(resourceType ResourceType, namespace string, name string) (int, bool) {
+	i, found := idx.byName[resourceType][namespace+"/"+name]
+	return i, found
+}
+
+// ForNamespace returns a new Data instance, with only the objects of this instance
+// that belongs to the given namespace (project)
+func (d *Data) ForNamespace(namespace string) *Data {
+	return d.subset(d.indexed().byNamespace[namespace])
+}
+
+// ForNamespaces returns a new Data instance, with only the objects of this instance
+// that belongs to the namespaces (projects) accepted by the given function
+func (d *Data) ForNamespaces(accept func(namespace string) bool) *Data {
+	p := make(positions)
+	for namespace, namespacePositions := range d.indexed().byNamespace {
+		if len(namespace) == 0 || !accept(namespace) {
+			continue
+		}
+		for resourceType, indexes := range namespacePositions {
+			p[resourceType] = append(p[resourceType], indexes...)
+		}
+	}
+	// the projects are indexed without namespace
+	for _, i := range d.indexed().byNamespace[""][ResourceTypeProject] {
+		if accept(d.Projects[i].Name) {
+			p.add(ResourceTypeProject, i)
+		}
+	}
+	for _, indexes := range p {
+		sort.Ints(indexes)
+	}
+	return d.subset(p)
+}
+
+// ForLabelValue returns a new Data instance, with only the objects of this instance
+// that have the given key/value label
+func (d *Data) ForLabelValue(labelKey string, labelValue string) *Data {
+	return d.subset(d.indexed().byLabel[labelKey+"="+labelValue])
+}
+
+// subset returns a new (indexed) Data instance, with the objects of this instance at the given positions.
+// The containers are the ones of the selected pods, and the applications the ones of the selected objects.
+func (d *Data) subset(p positions) *Data {
+	subset := &Data{Grouping: d.Grouping, RoutePolicy: d.RoutePolicy, CleanupPolicy: d.CleanupPolicy}
+	for _, i := range p[ResourceTypeProject] {
+		subset.Projects = append(subset.Projects, d.Projects[i])
+	}
+	for _, i := range p[ResourceTypeRoute] {
+		subset.Routes = append(subset.Routes, d.Routes[i])
+	}
+	for _, i := range p[ResourceTypeService] {
+		subset.Services = append(subset.Services, d.Services[i])
+	}
+	for _, i := range p[ResourceTypeEndpoints] {
+		subset.Endpoints = append(subset.Endpoints, d.Endpoints[i])
+	}
+	for _, i := range p[ResourceTypePod] {
+		subset.Pods = append(subset.Pods, d.Pods[i])
+		subset.Containers = append(subset.Containers, d.Pods[i].Spec.Containers...)
+	}
+	for _, i := range p[ResourceTypeImageStream] {
+		subset.ImageStreams = append(subset.ImageStreams, d.ImageStreams[i])
+	}
+	for _, i := range p[ResourceTypeBuildConfig] {
+		subset.BuildConfigs = append(subset.BuildConfigs, d.BuildConfigs[i])
+	}
+	for _, i := range p[ResourceTypeBuild] {
+		subset.Builds = append(subset.Builds, d.Builds[i])
+	}
+	for _, i := range p[ResourceTypeDeploymentConfig] {
+		subset.DeploymentConfigs = append(subset.DeploymentConfigs, d.DeploymentConfigs[i])
+	}
+	for _, i := range p[ResourceTypeReplicationController] {
+		subset.ReplicationControllers = append(subset.ReplicationControllers, d.ReplicationControllers[i])
+	}
+	for _, i := range p[ResourceTypeEvent] {
+		subset.Events = append(subset.Events, d.Events[i])
+	}
+	subset.ExtractApplications()
+	subset.BuildIndex()
+	return subset
+}
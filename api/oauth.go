@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	oauthapi "github.com/openshift/origin/pkg/oauth/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	k8client "k8s.io/kubernetes/pkg/client"
+)
+
+// OAuthClient is a client of the OpenShift OAuth Server,
+// used to authenticate users with the "authorization code" flow.
+// It is registered on the OpenShift side as an OAuthClient object,
+// with the same name, secret and redirect URI.
+type OAuthClient struct {
+	oauthapi.OAuthClient
+
+	// ServerURL is the public URL of the OAuth Server, used to redirect the users' browsers
+	ServerURL string
+
+	// tokenURL is the URL used to exchange an authorization code for an access token
+	tokenURL string
+
+	httpClient *http.Client
+}
+
+// OAuthToken is an access token returned by the OpenShift OAuth Server
+type OAuthToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// ExpiresAfter returns the duration after which the token will expire
+func (t *OAuthToken) ExpiresAfter() time.Duration {
+	return time.Duration(t.ExpiresIn) * time.Second
+}
+
+// NewOAuthClient builds a new OAuthClient instance, for the OAuth Server running on the API Server
+// used by this ClientWrapper.
+// The serverURL is the public URL of the OAuth Server (the master public URL): if it is empty,
+// the API Server host will be used.
+func (cw *ClientWrapper) NewOAuthClient(clientID string, clientSecret string, redirectURI string, serverURL string) (*OAuthClient, error) {
+	clientConfig, err := cw.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// we only need the TLS config to talk to the OAuth Server, not the credentials
+	transport, err := k8client.TransportFor(&k8client.Config{
+		Host:            clientConfig.Host,
+		TLSClientConfig: k8client.TLSClientConfig{CAFile: clientConfig.CAFile, CAData: clientConfig.CAData},
+		Insecure:        clientConfig.Insecure,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(serverURL) == 0 {
+		serverURL = clientConfig.Host
+	}
+
+	return &OAuthClient{
+		OAuthClient: oauthapi.OAuthClient{
+			ObjectMeta:   kapi.ObjectMeta{Name: clientID},
+			Secret:       clientSecret,
+			RedirectURIs: []string{redirectURI},
+		},
+		ServerURL:  strings.TrimSuffix(serverURL, "/"),
+		tokenURL:   strings.TrimSuffix(clientConfig.Host, "/") + "/oauth/token",
+		httpClient: &http.Client{Transport: transport, Timeout: 10 * time.Second},
+	}, nil
+}
+
+// RedirectURI returns the URI on which the OAuth Server will redirect the users once authenticated
+func (c *OAuthClient) RedirectURI() string {
+	if len(c.RedirectURIs) == 0 {
+		return ""
+	}
+	return c.RedirectURIs[0]
+}
+
+// AuthorizeURL returns the URL where the users should be redirected to authenticate.
+// The state will be given back as-is on the redirect URI.
+func (c *OAuthClient) AuthorizeURL(state string) string {
+	params := url.Values{}
+	params.Set("client_id", c.Name)
+	params.Set("response_type", "code")
+	params.Set("redirect_uri", c.RedirectURI())
+	params.Set("state", state)
+	return c.ServerURL + "/oauth/authorize?" + params.Encode()
+}
+
+// ExchangeCode exchanges the given authorization code for an access token
+func (c *OAuthClient) ExchangeCode(code string) (*OAuthToken, error) {
+	params := url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	params.Set("redirect_uri", c.RedirectURI())
+	params.Set("client_id", c.Name)
+	params.Set("client_secret", c.Secret)
+
+	resp, err := c.httpClient.PostForm(c.tokenURL, params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to exchange the authorization code: the OAuth Server answered with %v", resp.Status)
+	}
+
+	token := &OAuthToken{}
+	if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+		return nil, err
+	}
+	if len(token.AccessToken) == 0 {
+		return nil, fmt.Errorf("Failed to exchange the authorization code: no access token in the response!")
+	}
+
+	return token, nil
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"path"
+	"sync"
+	"time"
+)
+
+// Connection describes how to connect to the API Server.
+// If it is empty, the config made available when running in a cluster is used,
+// or the default config files (~/.kube/config) otherwise.
+type Connection struct {
+	// Kubeconfig is the path of a kubeconfig file
+	Kubeconfig string
+
+	// Context is the name of the context to use in the kubeconfig file (or its current context if empty)
+	Context string
+}
+
+// Options are the settings of a ClientWrapper, that can be changed while it is running.
+// They are shared by all the ClientWrapper instances derived from the same instance
+// (for the authenticated users, the refresher, ...)
+type Options struct {
+	// ResourcesCacheTTL is how long the resources are cached (if caching is enabled)
+	ResourcesCacheTTL time.Duration
+
+	// NamespacesCacheTTL is how long the available namespaces are cached
+	NamespacesCacheTTL time.Duration
+
+	// PermissionsCacheTTL is how long the results of the access reviews are cached
+	PermissionsCacheTTL time.Duration
+
+	// LoadTimeout is the maximum duration to load the data
+	LoadTimeout time.Duration
+
+	// SnapshotMaxAge is how old the snapshot of the data served to the pages can be,
+	// before a new one is loaded in the background (if caching is enabled)
+	SnapshotMaxAge time.Duration
+
+	// ResourceTypes are the resource types that can be loaded, or all of them if empty
+	ResourceTypes []ResourceType
+
+	// IncludedProjects are the patterns (such as "team-*") of the projects to display, or all of them if empty
+	IncludedProjects []string
+
+	// ExcludedProjects are the patterns of the projects to hide, even if they are included
+	ExcludedProjects []string
+
+	// Grouping describes how the objects are grouped in applications and secondary dimensions
+	Grouping Grouping
+
+	// RoutePolicy describes what is expected from the routes (allowed domains, certificates expiry)
+	RoutePolicy RoutePolicy
+
+	// CleanupPolicy describes when the resources are considered as unused (stale BuildConfigs, builds retention)
+	CleanupPolicy CleanupPolicy
+}
+
+// DefaultOptions returns the default options of a ClientWrapper
+func DefaultOptions() Options {
+	return Options{
+		ResourcesCacheTTL:   5 * time.Minute,
+		NamespacesCacheTTL:  5 * time.Minute,
+		PermissionsCacheTTL: 1 * time.Minute,
+		LoadTimeout:         10 * time.Second,
+		SnapshotMaxAge:      1 * time.Minute,
+	}
+}
+
+// IsResourceTypeEnabled returns true if the given resource type can be loaded
+func (o *Options) IsResourceTypeEnabled(resourceType ResourceType) bool {
+	if len(o.ResourceTypes) == 0 {
+		return true
+	}
+	for _, t := range o.ResourceTypes {
+		if t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProjectSelected returns true if the given project should be displayed
+func (o *Options) IsProjectSelected(project string) bool {
+	if len(o.IncludedProjects) > 0 && !matchesAny(o.IncludedProjects, project) {
+		return false
+	}
+	return !matchesAny(o.ExcludedProjects, project)
+}
+
+// matchesAny returns true if the given name matches one of the given patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedOptions are the options shared by the ClientWrapper instances
+type sharedOptions struct {
+	mutex   sync.RWMutex
+	options Options
+}
+
+// Options returns the current options of the ClientWrapper
+func (cw *ClientWrapper) Options() Options {
+	cw.options.mutex.RLock()
+	defer cw.options.mutex.RUnlock()
+
+	return cw.options.options
+}
+
+// SetOptions changes the options of the ClientWrapper,
+// and of all the instances derived from it
+func (cw *ClientWrapper) SetOptions(options Options) {
+	cw.options.mutex.Lock()
+	defer cw.options.mutex.Unlock()
+
+	cw.options.options = options
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// IsPodReady returns true if the given pod is running and ready to serve requests
+func IsPodReady(pod kapi.Pod) bool {
+	if pod.Status.Phase != kapi.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == kapi.PodReady {
+			return condition.Status == kapi.ConditionTrue
+		}
+	}
+	return false
+}
+
+// RestartCountOf returns the number of restarts of all the containers of the given pod
+func RestartCountOf(pod kapi.Pod) int {
+	restarts := 0
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
+
+// PodsOfDeployment returns the pods created by the given deployment (ReplicationController)
+func (d *Data) PodsOfDeployment(rc kapi.ReplicationController) []kapi.Pod {
+	pods := []kapi.Pod{}
+	for _, i := range d.indexed().podsByRC[rc.Namespace+"/"+rc.Name] {
+		pods = append(pods, d.Pods[i])
+	}
+	return pods
+}
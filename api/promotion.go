@@ -0,0 +1,253 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+)
+
+// DeployedImage is the image deployed by a DeploymentConfig in an environment, and the build that produced it
+type DeployedImage struct {
+	Environment      string
+	Namespace        string
+	DeploymentConfig string
+	Container        string
+
+	// Image is the reference of the image, such as "172.30.1.1:5000/myapp-dev/myapp@sha256:1234..."
+	Image string
+
+	// Digest is the digest of the image, such as "sha256:1234...", if it is known
+	Digest string
+
+	// Tags are the ImageStream tags that currently point to the image, such as "myapp-dev/myapp:latest"
+	Tags []string
+
+	// Build is the build that produced the image, if it has been found
+	Build *buildapi.Build
+
+	// Drift describes how the image differs from the one of the previous environment,
+	// such as "3 builds behind staging", or is empty if it is the same image
+	Drift string
+}
+
+// ShortDigest returns the first characters of the digest of the image (without the algorithm), or an empty string
+func (i *DeployedImage) ShortDigest() string {
+	return shortDigest(i.Digest)
+}
+
+// Commit returns the commit of the source code of the image, from its build, or an empty string
+func (i *DeployedImage) Commit() string {
+	if i.Build == nil || i.Build.Spec.Revision == nil || i.Build.Spec.Revision.Git == nil {
+		return ""
+	}
+	return i.Build.Spec.Revision.Git.Commit
+}
+
+// Promotion is the images of a component of an application (the DeploymentConfigs with the same name)
+// in each environment
+type Promotion struct {
+	Application string
+	Name        string
+
+	// Images are the deployed images, by environment
+	Images map[string]*DeployedImage
+}
+
+// HasDrift returns true if the images are not the same in all the environments
+func (p *Promotion) HasDrift() bool {
+	for _, image := range p.Images {
+		if len(image.Drift) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// PromotionMatrix is the images deployed in each environment, for all the components of the applications
+type PromotionMatrix struct {
+	// Environments are the environments with at least one deployed image, in the order of the promotions
+	Environments []string
+
+	// Promotions are sorted by application and name
+	Promotions []Promotion
+}
+
+// PromotionMatrix returns the images deployed by the DeploymentConfigs of the applications in each environment.
+// The images are resolved from the deployments (and the image change triggers of the DeploymentConfigs),
+// and matched with the history of the ImageStream tags to find their digests and the builds that produced them.
+// The DeploymentConfigs without application or environment are ignored.
+func (d *Data) PromotionMatrix() *PromotionMatrix {
+	history := newImageHistory(d)
+	matrix := &PromotionMatrix{}
+
+	environments := make(map[string]bool)
+	promotions := make(map[string]*Promotion)
+	keys := []string{}
+	for _, dc := range d.DeploymentConfigs {
+		application, environment := d.ApplicationOf(dc.Labels), d.EnvironmentOf(dc.ObjectMeta)
+		if len(application) == 0 || len(environment) == 0 {
+			continue
+		}
+		image := d.deployedImageOf(dc, history)
+		if image == nil {
+			continue
+		}
+		image.Environment = environment
+		environments[environment] = true
+
+		key := application + "/" + dc.Name
+		promotion, found := promotions[key]
+		if !found {
+			promotion = &Promotion{
+				Application: application,
+				Name:        dc.Name,
+				Images:      make(map[string]*DeployedImage),
+			}
+			promotions[key] = promotion
+			keys = append(keys, key)
+		}
+		// keep a single DeploymentConfig per environment, the first one by namespace
+		if previous, found := promotion.Images[environment]; !found || dc.Namespace < previous.Namespace {
+			promotion.Images[environment] = image
+		}
+	}
+
+	for environment := range environments {
+		matrix.Environments = append(matrix.Environments, environment)
+	}
+	d.Grouping.sortEnvironments(matrix.Environments)
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		promotion := promotions[key]
+		d.detectDrift(promotion, matrix.Environments)
+		matrix.Promotions = append(matrix.Promotions, *promotion)
+	}
+	return matrix
+}
+
+// deployedImageOf returns the image deployed by the given DeploymentConfig (by its latest deployment if any),
+// for the container of its image change trigger (or its first container), or nil if it has no containers
+func (d *Data) deployedImageOf(dc deployapi.DeploymentConfig, history *imageHistory) *DeployedImage {
+	template := dc.Template.ControllerTemplate.Template
+	if rc := d.LatestDeploymentOf(dc); rc != nil && rc.Spec.Template != nil {
+		template = rc.Spec.Template
+	}
+	if template == nil || len(template.Spec.Containers) == 0 {
+		return nil
+	}
+
+	container := template.Spec.Containers[0]
+	var params *deployapi.DeploymentTriggerImageChangeParams
+	for _, trigger := range dc.Triggers {
+		if trigger.Type != deployapi.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil {
+			continue
+		}
+		params = trigger.ImageChangeParams
+		for _, c := range template.Spec.Containers {
+			if len(params.ContainerNames) > 0 && c.Name == params.ContainerNames[0] {
+				container = c
+			}
+		}
+		break
+	}
+
+	image := &DeployedImage{
+		Namespace:        dc.Namespace,
+		DeploymentConfig: dc.Name,
+		Container:        container.Name,
+		Image:            container.Image,
+	}
+	if ref, err := imageapi.ParseDockerImageReference(container.Image); err == nil && len(ref.ID) > 0 {
+		image.Digest = ref.ID
+	}
+	if len(image.Digest) == 0 {
+		image.Digest = history.digests[container.Image]
+	}
+	if len(image.Digest) == 0 && params != nil && len(params.LastTriggeredImage) > 0 {
+		if ref, err := imageapi.ParseDockerImageReference(params.LastTriggeredImage); err == nil && len(ref.ID) > 0 {
+			image.Digest = ref.ID
+		} else {
+			image.Digest = history.digests[params.LastTriggeredImage]
+		}
+	}
+
+	if len(image.Digest) > 0 {
+		image.Tags = history.tags[image.Digest]
+		image.Build = history.buildOf(image.Digest)
+	}
+	return image
+}
+
+// detectDrift sets the drift of each image of the given promotion,
+// compared to the image of the previous environment (in the given order)
+func (d *Data) detectDrift(promotion *Promotion, environments []string) {
+	var previous *DeployedImage
+	for _, environment := range environments {
+		image, found := promotion.Images[environment]
+		if !found {
+			continue
+		}
+		if previous != nil && !sameImage(previous, image) {
+			image.Drift = d.driftBetween(previous, image)
+		}
+		previous = image
+	}
+}
+
+// sameImage returns true if the given images are the same, by digest (or by reference if a digest is unknown)
+func sameImage(a *DeployedImage, b *DeployedImage) bool {
+	if len(a.Digest) > 0 && len(b.Digest) > 0 {
+		return a.Digest == b.Digest
+	}
+	return a.Image == b.Image
+}
+
+// driftBetween describes how the given image differs from the image of the previous environment:
+// by the number of builds between them if they have been built by the same BuildConfig
+func (d *Data) driftBetween(previous *DeployedImage, image *DeployedImage) string {
+	if previous.Build == nil || image.Build == nil ||
+		previous.Build.Namespace != image.Build.Namespace ||
+		BuildConfigNameOf(*previous.Build) != BuildConfigNameOf(*image.Build) {
+		return fmt.Sprintf("different image than %v", previous.Environment)
+	}
+
+	bc, found := d.FindBuildConfig(image.Build.Namespace, BuildConfigNameOf(*image.Build))
+	if !found {
+		return fmt.Sprintf("different build than %v", previous.Environment)
+	}
+
+	// the complete builds of the BuildConfig, the most recent first
+	position := make(map[string]int)
+	for _, build := range d.BuildsOf(*bc) {
+		if build.Status.Phase == buildapi.BuildPhaseComplete {
+			position[build.Name] = len(position)
+		}
+	}
+	// the builds may have been pruned (or may not be complete), and then can't be compared
+	imagePosition, found := position[image.Build.Name]
+	previousPosition, previousFound := position[previous.Build.Name]
+	if !found || !previousFound {
+		return fmt.Sprintf("different build than %v", previous.Environment)
+	}
+	count := imagePosition - previousPosition
+	switch {
+	case count > 0:
+		return fmt.Sprintf("%v behind %v", pluralize(count, "build"), previous.Environment)
+	case count < 0:
+		return fmt.Sprintf("%v ahead of %v", pluralize(-count, "build"), previous.Environment)
+	default:
+		return fmt.Sprintf("different build than %v", previous.Environment)
+	}
+}
+
+// pluralize returns the given count with the given noun, such as "1 build" or "3 builds"
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// RefreshListener is notified with the fresh data after each successful refresh
+type RefreshListener func(data *Data, refreshedAt time.Time)
+
+// Refresher periodically loads fresh data for all the resource types,
+// and notifies its listeners (alerting rules, ...) with the new data.
+// The data are always retrieved from the API, bypassing the cache,
+// and are also stored as the latest snapshot of the ClientWrapper, served to the pages.
+type Refresher struct {
+	clientWrapper *ClientWrapper
+	interval      time.Duration
+
+	mutex       sync.RWMutex
+	listeners   []RefreshListener
+	data        *Data
+	refreshedAt time.Time
+}
+
+// NewRefresher builds a new Refresher instance, that will use the credentials of this ClientWrapper
+// to refresh the data at the given interval.
+func (cw *ClientWrapper) NewRefresher(interval time.Duration) *Refresher {
+	return &Refresher{
+		clientWrapper: cw.withoutResourcesCache(),
+		interval:      interval,
+	}
+}
+
+// AddListener registers the given listener, which will be notified after each successful refresh
+func (r *Refresher) AddListener(listener RefreshListener) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.listeners = append(r.listeners, listener)
+}
+
+// HasListeners returns true if at least one listener has been registered
+func (r *Refresher) HasListeners() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.listeners) > 0
+}
+
+// Run refreshes the data right away, and then at each interval, until the stop channel is closed.
+// It blocks, so it should be run in its own goroutine.
+func (r *Refresher) Run(stop <-chan struct{}) {
+	for {
+		if err := r.Refresh(); err != nil {
+			log.Printf("Failed to refresh the data: %v", err)
+		}
+
+		select {
+		case <-time.After(r.Interval()):
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Interval returns the interval between two refreshes
+func (r *Refresher) Interval() time.Duration {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.interval
+}
+
+// SetInterval changes the interval between two refreshes, starting after the next refresh
+func (r *Refresher) SetInterval(interval time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.interval = interval
+}
+
+// Refresh loads fresh data, stores them as the latest snapshot, and notifies the listeners
+func (r *Refresher) Refresh() error {
+	snapshot, err := r.clientWrapper.LoadSnapshot()
+	if err != nil {
+		return err
+	}
+	data, refreshedAt := snapshot.Data, snapshot.LoadedAt
+
+	r.mutex.Lock()
+	r.data = data
+	r.refreshedAt = refreshedAt
+	listeners := r.listeners
+	r.mutex.Unlock()
+
+	for _, listener := range listeners {
+		listener(data, refreshedAt)
+	}
+	return nil
+}
+
+// Data returns the data retrieved by the latest successful refresh, and the time of the refresh.
+// The data are nil if there has been no successful refresh yet.
+func (r *Refresher) Data() (*Data, time.Time) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.data, r.refreshedAt
+}
@@ -9,6 +9,7 @@ const (
 	ResourceTypeProject               ResourceType = "project"
 	ResourceTypeRoute                 ResourceType = "route"
 	ResourceTypeService               ResourceType = "service"
+	ResourceTypeEndpoints             ResourceType = "endpoints"
 	ResourceTypePod                   ResourceType = "pod"
 	ResourceTypeContainer             ResourceType = "container"
 	ResourceTypeImageStream           ResourceType = "imagestream"
@@ -25,6 +26,7 @@ var (
 		ResourceTypeProject,
 		ResourceTypeRoute,
 		ResourceTypeService,
+		ResourceTypeEndpoints,
 		ResourceTypePod,
 		ResourceTypeContainer,
 		ResourceTypeImageStream,
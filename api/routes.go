@@ -0,0 +1,214 @@
+package api
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+// RoutePolicy describes what is expected from the routes
+type RoutePolicy struct {
+	// AllowedDomains are the domains of the hosts of the routes (such as "apps.example.com"), or any domain if empty
+	AllowedDomains []string
+
+	// CertificateExpiry is how long before their expiry the certificates of the routes are flagged
+	CertificateExpiry time.Duration
+}
+
+// DefaultCertificateExpiry is how long before their expiry the certificates are flagged, if the policy doesn't define it
+const DefaultCertificateExpiry = 30 * 24 * time.Hour
+
+// certificateExpiry returns how long before their expiry the certificates are flagged
+func (p RoutePolicy) certificateExpiry() time.Duration {
+	if p.CertificateExpiry <= 0 {
+		return DefaultCertificateExpiry
+	}
+	return p.CertificateExpiry
+}
+
+// IsAllowedHost returns true if the given host is in one of the allowed domains (or if any domain is allowed)
+func (p RoutePolicy) IsAllowedHost(host string) bool {
+	if len(p.AllowedDomains) == 0 {
+		return true
+	}
+	for _, domain := range p.AllowedDomains {
+		domain = strings.TrimPrefix(strings.TrimPrefix(domain, "*"), ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteCertificate is the certificate of a route, parsed from its TLS config
+type RouteCertificate struct {
+	Subject string
+	Issuer  string
+
+	// DNSNames are the subject alternative names of the certificate
+	DNSNames []string
+
+	NotBefore time.Time
+	NotAfter  time.Time
+
+	// ExpiresSoon is true if the certificate has expired, or expires before the expiry delay of the policy
+	ExpiresSoon bool
+}
+
+// RouteReport is the analysis of a route: its certificate, and its problems
+type RouteReport struct {
+	Route routeapi.Route
+
+	// Certificate is the certificate of the route, if it has one (and it can be parsed)
+	Certificate *RouteCertificate
+
+	// Problems are the misconfigurations of the route, such as "service myapp not found"
+	Problems []string
+}
+
+// RouteReports analyzes the routes of this Data instance, at the given time:
+// their certificates, the hosts (and paths) claimed by several routes, the routes pointing at missing services
+// (or at services without ports), and the hosts outside of the allowed domains.
+func (d *Data) RouteReports(now time.Time) []RouteReport {
+	claims := d.routeClaims()
+
+	reports := []RouteReport{}
+	for _, route := range d.Routes {
+		report := RouteReport{Route: route, Problems: []string{}}
+		problem := func(format string, args ...interface{}) {
+			report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+		}
+
+		if len(route.Host) == 0 {
+			problem("no host")
+		} else {
+			claim := route.Host + routePathOf(route)
+			if owners := claims[claim]; len(owners) > 1 {
+				if first := owners[0]; first.Namespace != route.Namespace || first.Name != route.Name {
+					problem("%v already claimed by %v/%v", claim, first.Namespace, first.Name)
+				} else {
+					for _, other := range owners[1:] {
+						problem("%v also claimed by %v/%v", claim, other.Namespace, other.Name)
+					}
+				}
+			}
+			if !d.RoutePolicy.IsAllowedHost(route.Host) {
+				problem("host %v is outside of the allowed domains", route.Host)
+			}
+		}
+
+		// the services are not checked if they have not been loaded
+		if d.Services != nil {
+			if i, found := d.indexed().find(ResourceTypeService, route.Namespace, route.ServiceName); !found {
+				problem("service %v not found", route.ServiceName)
+			} else if len(d.Services[i].Spec.Ports) == 0 {
+				problem("service %v has no ports", route.ServiceName)
+			}
+		}
+
+		if route.TLS != nil && len(route.TLS.Certificate) > 0 {
+			certificate, err := parseCertificate(route.TLS.Certificate)
+			switch {
+			case err != nil:
+				problem("invalid certificate: %v", err)
+			case now.After(certificate.NotAfter):
+				certificate.ExpiresSoon = true
+				problem("certificate expired on %v", certificate.NotAfter.Format("2006-01-02"))
+			case certificate.NotAfter.Sub(now) < d.RoutePolicy.certificateExpiry():
+				certificate.ExpiresSoon = true
+				problem("certificate expires in %v", pluralize(int(certificate.NotAfter.Sub(now).Hours()/24), "day"))
+			}
+			if certificate != nil && len(route.Host) > 0 && !certificate.matches(route.Host) {
+				problem("certificate doesn't match host %v", route.Host)
+			}
+			report.Certificate = certificate
+		}
+
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// routeClaims returns the routes (the oldest first) that claim each host and path, such as "myapp.example.com/api"
+func (d *Data) routeClaims() map[string][]routeapi.Route {
+	claims := make(map[string][]routeapi.Route)
+	for _, route := range d.Routes {
+		if len(route.Host) == 0 {
+			continue
+		}
+		claim := route.Host + routePathOf(route)
+		claims[claim] = append(claims[claim], route)
+	}
+	for _, routes := range claims {
+		sort.Sort(routesByCreation(routes))
+	}
+	return claims
+}
+
+// routePathOf returns the path of the given route, or "/" if it has none
+func routePathOf(route routeapi.Route) string {
+	if len(route.Path) == 0 {
+		return "/"
+	}
+	return route.Path
+}
+
+// parseCertificate parses the first certificate of the given PEM content
+func parseCertificate(content string) (*RouteCertificate, error) {
+	block, _ := pem.Decode([]byte(content))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &RouteCertificate{
+		Subject:   cert.Subject.CommonName,
+		Issuer:    cert.Issuer.CommonName,
+		DNSNames:  cert.DNSNames,
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+	}, nil
+}
+
+// matches returns true if the certificate is valid for the given host,
+// by its subject alternative names (or its subject if it has none), which can be wildcards such as "*.example.com"
+func (c *RouteCertificate) matches(host string) bool {
+	names := c.DNSNames
+	if len(names) == 0 {
+		names = []string{c.Subject}
+	}
+	for _, name := range names {
+		if name == host {
+			return true
+		}
+		if strings.HasPrefix(name, "*.") {
+			if i := strings.Index(host, "."); i > 0 && host[i+1:] == name[2:] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// routesByCreation sorts the routes by creation time, and then by namespace and name
+type routesByCreation []routeapi.Route
+
+func (r routesByCreation) Len() int      { return len(r) }
+func (r routesByCreation) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r routesByCreation) Less(i, j int) bool {
+	ti, tj := r[i].CreationTimestamp, r[j].CreationTimestamp
+	if !ti.Equal(tj) {
+		return ti.Before(tj)
+	}
+	if r[i].Namespace != r[j].Namespace {
+		return r[i].Namespace < r[j].Namespace
+	}
+	return r[i].Name < r[j].Name
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/util"
+)
+
+// FindEndpoints returns the endpoints with the given namespace and name
+// (the endpoints of a service have the same name as the service)
+func (d *Data) FindEndpoints(namespace string, name string) (*kapi.Endpoints, bool) {
+	if i, found := d.indexed().find(ResourceTypeEndpoints, namespace, name); found {
+		return &d.Endpoints[i], true
+	}
+	return nil, false
+}
+
+// AddressesCountOf returns the number of addresses of the given endpoints
+func AddressesCountOf(endpoints kapi.Endpoints) int {
+	count := 0
+	for _, subset := range endpoints.Subsets {
+		count += len(subset.Addresses)
+	}
+	return count
+}
+
+// ServiceReport is the analysis of a service: the pods it selects, its ready endpoints, and its problems
+type ServiceReport struct {
+	Service kapi.Service
+
+	// Pods and ReadyPods are the number of pods selected by the service, and of the ready ones
+	Pods      int
+	ReadyPods int
+
+	// Endpoints is the number of ready endpoints of the service, or -1 if its endpoints are unknown
+	Endpoints int
+
+	// Applications are the applications of the selected pods, sorted
+	Applications []string
+
+	// Problems are the misconfigurations of the service, such as "selects no pods"
+	Problems []string
+}
+
+// ServiceReports analyzes the services of this Data instance: the services that select no pods,
+// that have no ready endpoints, that select the pods of several applications,
+// or whose ports don't match any container port of the selected pods.
+// The services without selector (whose endpoints are managed manually) are only checked for their endpoints.
+func (d *Data) ServiceReports() []ServiceReport {
+	reports := []ServiceReport{}
+	for _, service := range d.Services {
+		report := ServiceReport{Service: service, Endpoints: -1, Applications: []string{}, Problems: []string{}}
+		problem := func(format string, args ...interface{}) {
+			report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+		}
+
+		pods := d.PodsOfService(service)
+		applications := make(map[string]bool)
+		for _, pod := range pods {
+			report.Pods++
+			if IsPodReady(pod) {
+				report.ReadyPods++
+			}
+			if application := d.ApplicationOf(pod.Labels); len(application) > 0 && !applications[application] {
+				applications[application] = true
+				report.Applications = append(report.Applications, application)
+			}
+		}
+		sort.Strings(report.Applications)
+
+		if endpoints, found := d.FindEndpoints(service.Namespace, service.Name); found {
+			report.Endpoints = AddressesCountOf(*endpoints)
+		} else if d.Endpoints != nil {
+			report.Endpoints = 0
+		}
+
+		if len(service.Spec.Selector) > 0 {
+			switch {
+			case report.Pods == 0:
+				problem("selector %v selects no pods", labels.SelectorFromSet(service.Spec.Selector))
+			case len(report.Applications) > 1:
+				problem("selector %v selects the pods of several applications: %v",
+					labels.SelectorFromSet(service.Spec.Selector), strings.Join(report.Applications, ", "))
+			}
+		}
+		if report.Endpoints == 0 && (report.Pods > 0 || len(service.Spec.Selector) == 0) {
+			problem("no ready endpoints (%d of %d pods ready)", report.ReadyPods, report.Pods)
+		}
+
+		if report.Pods > 0 {
+			for _, port := range service.Spec.Ports {
+				if !matchesContainerPort(port, pods) {
+					problem("port %v doesn't match any container port of the pods", servicePortName(port))
+				}
+			}
+		}
+
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// PodsOfService returns the pods selected by the selector of the given service (none if it has no selector)
+func (d *Data) PodsOfService(service kapi.Service) []kapi.Pod {
+	pods := []kapi.Pod{}
+	if len(service.Spec.Selector) == 0 {
+		return pods
+	}
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for _, i := range d.indexed().byNamespace[service.Namespace][ResourceTypePod] {
+		if selector.Matches(labels.Set(d.Pods[i].Labels)) {
+			pods = append(pods, d.Pods[i])
+		}
+	}
+	return pods
+}
+
+// matchesContainerPort returns true if the target port of the given service port
+// (a number, or the name of a port) is a port of a container of one of the given pods
+func matchesContainerPort(port kapi.ServicePort, pods []kapi.Pod) bool {
+	target := port.TargetPort
+	if target.Kind == util.IntstrInt && target.IntVal == 0 {
+		// the target port defaults to the port of the service
+		target = util.NewIntOrStringFromInt(port.Port)
+	}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if len(port.Protocol) > 0 && len(containerPort.Protocol) > 0 && port.Protocol != containerPort.Protocol {
+					continue
+				}
+				if (target.Kind == util.IntstrInt && target.IntVal == containerPort.ContainerPort) ||
+					(target.Kind == util.IntstrString && target.StrVal == containerPort.Name) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// servicePortName returns a description of the given service port, such as "8080/TCP (target http)"
+func servicePortName(port kapi.ServicePort) string {
+	name := fmt.Sprintf("%d/%v", port.Port, port.Protocol)
+	if len(port.Name) > 0 {
+		name = port.Name + " " + name
+	}
+	if target := port.TargetPort.String(); target != "0" && target != fmt.Sprintf("%d", port.Port) {
+		name += " (target " + target + ")"
+	}
+	return name
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Snapshot is the data of all the resource types, as loaded at a given time
+type Snapshot struct {
+	*Data
+
+	// LoadedAt is the time the data were loaded
+	LoadedAt time.Time
+}
+
+// Age returns how old the data of the snapshot are
+func (s *Snapshot) Age() time.Duration {
+	return time.Since(s.LoadedAt)
+}
+
+// snapshotStore stores the latest snapshot of a ClientWrapper,
+// and coalesces the concurrent loads of a new snapshot into a single one.
+// It is shared by the ClientWrapper instances that use the same credentials (such as the refresher).
+type snapshotStore struct {
+	mutex   sync.Mutex
+	latest  *Snapshot
+	loading *snapshotLoad
+}
+
+// snapshotLoad is a load of a new snapshot, in progress or done
+type snapshotLoad struct {
+	done     chan struct{}
+	snapshot *Snapshot
+	err      error
+}
+
+// newSnapshotStore builds a new (empty) snapshotStore instance
+func newSnapshotStore() *snapshotStore {
+	return &snapshotStore{}
+}
+
+// Snapshot returns the latest snapshot of the data (for all the resource types), without waiting for the API:
+// if the snapshot is older than the max age from the options, a new one is loaded in the background,
+// and the current one is returned in the meantime.
+// It only waits for the API if there is no snapshot yet, or if caching is disabled.
+// The concurrent loads are coalesced, so that a single request is sent to the API for each resource type.
+func (cw *ClientWrapper) Snapshot() (*Snapshot, error) {
+	store := cw.snapshots
+
+	store.mutex.Lock()
+	latest := store.latest
+	if latest != nil && cw.resourcesCache != nil {
+		if latest.Age() > cw.Options().SnapshotMaxAge {
+			cw.startSnapshotLoad()
+		}
+		store.mutex.Unlock()
+		return latest, nil
+	}
+	store.mutex.Unlock()
+
+	return cw.LoadSnapshot()
+}
+
+// LoadSnapshot loads a new snapshot of the data from the API (or waits for the load in progress, if any),
+// and stores it as the latest snapshot
+func (cw *ClientWrapper) LoadSnapshot() (*Snapshot, error) {
+	cw.snapshots.mutex.Lock()
+	load := cw.startSnapshotLoad()
+	cw.snapshots.mutex.Unlock()
+
+	<-load.done
+	return load.snapshot, load.err
+}
+
+// startSnapshotLoad starts to load a new snapshot in the background, unless a load is already in progress,
+// and returns the load in progress. The mutex of the snapshots must be locked.
+func (cw *ClientWrapper) startSnapshotLoad() *snapshotLoad {
+	store := cw.snapshots
+	if store.loading != nil {
+		return store.loading
+	}
+
+	load := &snapshotLoad{
+		done: make(chan struct{}),
+	}
+	store.loading = load
+
+	go func() {
+		// always retrieve fresh data from the API: the snapshot replaces the cache of the resources
+		data, err := cw.withoutResourcesCache().LoadData(ResourceTypeAll...)
+		if err != nil {
+			log.Printf("Failed to load a new snapshot of the data: %v", err)
+			load.err = err
+		} else {
+			load.snapshot = &Snapshot{Data: data, LoadedAt: time.Now()}
+		}
+
+		store.mutex.Lock()
+		if load.snapshot != nil {
+			store.latest = load.snapshot
+		}
+		store.loading = nil
+		store.mutex.Unlock()
+
+		close(load.done)
+	}()
+	return load
+}
@@ -0,0 +1,362 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// ImageConsumer is an object that uses an image: a BuildConfig (as the base image of its builds),
+// a DeploymentConfig (by an image change trigger), or an ImageStream tag (that tracks another image)
+type ImageConsumer struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Image is the key of the image it uses, such as "openshift/ruby:2.2" for an ImageStream tag,
+	// or "docker.io/centos:7" for a Docker image
+	Image string `json:"image"`
+
+	// Automatic is true if it is updated when the image changes: by an (automatic) image change trigger,
+	// or for an ImageStream tag, if it tracks another tag of the same ImageStream
+	Automatic bool `json:"automatic"`
+
+	// Output is the key of the image it produces (the output ImageStream tag of a BuildConfig, or the ImageStream tag itself),
+	// or an empty string
+	Output string `json:"output,omitempty"`
+}
+
+// BrokenLink is a reference from an object to an ImageStream tag that doesn't exist (or has no image yet)
+type BrokenLink struct {
+	ImageConsumer
+
+	// Reason is why the link is broken, such as "ImageStream myproject/myapp not found"
+	Reason string `json:"reason"`
+}
+
+// Impact is an object that is updated (or should be updated) when an image changes
+type Impact struct {
+	ImageConsumer
+
+	// Depth is the number of links between the changed image and the object (1 for the direct consumers)
+	Depth int `json:"depth"`
+
+	// Path are the keys of the images between the changed image and the object, from the changed image
+	Path []string `json:"path"`
+
+	// Automatic is true if all the links from the changed image are automatic:
+	// the BuildConfigs rebuild and the DeploymentConfigs redeploy without any action
+	Automatic bool `json:"automatic"`
+}
+
+// SupplyChain is the graph of the images: the base images used by the BuildConfigs, their output ImageStream tags,
+// and the images deployed by the DeploymentConfigs
+type SupplyChain struct {
+	// consumers are the consumers of each image, by key
+	consumers map[string][]ImageConsumer
+
+	// images are the keys of all the images of the graph
+	images map[string]bool
+
+	// Broken are the references to missing ImageStream tags
+	Broken []BrokenLink
+}
+
+// SupplyChain builds the graph of the images, from the BuildConfigs (their "from" references, image change triggers
+// and outputs), the DeploymentConfigs (their image change triggers) and the ImageStreams (their tags that track other images)
+func (d *Data) SupplyChain() *SupplyChain {
+	sc := &SupplyChain{
+		consumers: make(map[string][]ImageConsumer),
+		images:    make(map[string]bool),
+	}
+
+	for _, is := range d.ImageStreams {
+		for _, tag := range sortedSpecTagsOf(is) {
+			ref := is.Spec.Tags[tag].From
+			if ref == nil {
+				continue
+			}
+			from := *ref
+			if from.Kind == "ImageStreamTag" && !strings.Contains(from.Name, ":") {
+				// a tag of the same ImageStream
+				from.Name = is.Name + ":" + from.Name
+			}
+			consumer := ImageConsumer{
+				Kind:      "ImageStreamTag",
+				Namespace: is.Namespace,
+				Name:      is.Name + ":" + tag,
+				Image:     imageKey(from, is.Namespace),
+				Output:    is.Namespace + "/" + is.Name + ":" + tag,
+			}
+			// only the tags that track another tag of the same ImageStream are updated automatically,
+			// the Docker images must be imported again
+			if from.Kind == "ImageStreamTag" && strings.HasPrefix(consumer.Image, is.Namespace+"/"+is.Name+":") {
+				consumer.Automatic = true
+			}
+			sc.images[consumer.Output] = true
+			sc.add(d, is.ObjectMeta, consumer, from)
+		}
+	}
+
+	for _, bc := range d.BuildConfigs {
+		from := strategyFromOf(bc.Spec.Strategy)
+		if from == nil {
+			continue
+		}
+		consumer := ImageConsumer{
+			Kind:      "BuildConfig",
+			Namespace: bc.Namespace,
+			Name:      bc.Name,
+			Image:     imageKey(*from, bc.Namespace),
+		}
+		for _, trigger := range bc.Spec.Triggers {
+			if trigger.Type != buildapi.ImageChangeBuildTriggerType && trigger.Type != buildapi.ImageChangeBuildTriggerTypeDeprecated {
+				continue
+			}
+			// without a "from" reference, the trigger is on the base image of the strategy
+			if trigger.ImageChange == nil || trigger.ImageChange.From == nil || imageKey(*trigger.ImageChange.From, bc.Namespace) == consumer.Image {
+				consumer.Automatic = true
+			}
+		}
+		if to := bc.Spec.Output.To; to != nil && to.Kind != "DockerImage" {
+			consumer.Output = imageKey(*to, bc.Namespace)
+			sc.images[consumer.Output] = true
+		}
+		sc.add(d, bc.ObjectMeta, consumer, *from)
+	}
+
+	for _, dc := range d.DeploymentConfigs {
+		for _, trigger := range dc.Triggers {
+			if trigger.Type != deployapi.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil {
+				continue
+			}
+			params := trigger.ImageChangeParams
+			from := params.From
+			if len(from.Kind) == 0 || from.Kind == "ImageRepository" || from.Kind == "ImageStream" {
+				// the deprecated form of the trigger, with the tag in its own field
+				tag := params.Tag
+				if len(tag) == 0 {
+					tag = imageapi.DefaultImageTag
+				}
+				from = kapi.ObjectReference{Kind: "ImageStreamTag", Namespace: from.Namespace, Name: from.Name + ":" + tag}
+			}
+			sc.add(d, dc.ObjectMeta, ImageConsumer{
+				Kind:      "DeploymentConfig",
+				Namespace: dc.Namespace,
+				Name:      dc.Name,
+				Image:     imageKey(from, dc.Namespace),
+				Automatic: params.Automatic,
+			}, from)
+		}
+	}
+
+	sort.Sort(brokenLinksByObject(sc.Broken))
+	return sc
+}
+
+// add adds the given consumer of an image (referenced by the given object reference) to the graph,
+// or a broken link if it is a missing ImageStream tag
+func (sc *SupplyChain) add(d *Data, meta kapi.ObjectMeta, consumer ImageConsumer, from kapi.ObjectReference) {
+	sc.images[consumer.Image] = true
+	sc.consumers[consumer.Image] = append(sc.consumers[consumer.Image], consumer)
+	if reason := d.missingImageOf(from, meta.Namespace); len(reason) > 0 {
+		sc.Broken = append(sc.Broken, BrokenLink{ImageConsumer: consumer, Reason: reason})
+	}
+}
+
+// Images returns the keys of the images of the graph that match the given query, sorted:
+// an ImageStream ("namespace/name") or one of its tags ("namespace/name:tag"),
+// or a Docker image repository (such as "openshift/origin-base") or one of its tags
+func (sc *SupplyChain) Images(query string) []string {
+	queryRepository, queryTag := repositoryAndTagOf(query)
+	matching := []string{}
+	for image := range sc.images {
+		repository, tag := repositoryAndTagOf(image)
+		if len(tag) == 0 {
+			tag = imageapi.DefaultImageTag
+		}
+		if len(query) == 0 || (repository == queryRepository && (len(queryTag) == 0 || tag == queryTag)) {
+			matching = append(matching, image)
+		}
+	}
+	sort.Strings(matching)
+	return matching
+}
+
+// Impacts returns the objects that are updated (or should be updated) when one of the images matching the given query changes:
+// the BuildConfigs that rebuild, the ImageStream tags that are updated, and the DeploymentConfigs that redeploy,
+// following the outputs of the BuildConfigs (and ImageStream tags) to the next consumers.
+// They are sorted by depth, and then by kind, namespace and name.
+func (sc *SupplyChain) Impacts(query string) []Impact {
+	impacts := []Impact{}
+	visited := make(map[string]bool)
+
+	type step struct {
+		image     string
+		path      []string
+		automatic bool
+	}
+	queue := []step{}
+	for _, image := range sc.Images(query) {
+		queue = append(queue, step{image: image, path: []string{image}, automatic: true})
+		visited[image] = true
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, consumer := range sc.consumers[current.image] {
+			impact := Impact{
+				ImageConsumer: consumer,
+				Depth:         len(current.path),
+				Path:          current.path,
+				Automatic:     current.automatic && consumer.Automatic,
+			}
+			impacts = append(impacts, impact)
+
+			if len(consumer.Output) > 0 && !visited[consumer.Output] {
+				visited[consumer.Output] = true
+				path := append(append([]string{}, current.path...), consumer.Output)
+				queue = append(queue, step{image: consumer.Output, path: path, automatic: impact.Automatic})
+			}
+		}
+	}
+	sort.Sort(impactsByDepth(impacts))
+	return impacts
+}
+
+// missingImageOf returns why the image referenced by the given object reference (from an object of the given namespace)
+// is missing, or an empty string if it exists (or if it is not an ImageStream tag)
+func (d *Data) missingImageOf(from kapi.ObjectReference, namespace string) string {
+	if from.Kind != "ImageStreamTag" {
+		return ""
+	}
+	if len(from.Namespace) > 0 {
+		namespace = from.Namespace
+	}
+	name, tag := splitImageStreamTag(from.Name)
+
+	i, found := d.indexed().find(ResourceTypeImageStream, namespace, name)
+	if !found {
+		if d.isVisibleNamespace(namespace) {
+			return fmt.Sprintf("ImageStream %v/%v not found", namespace, name)
+		}
+		// the ImageStreams of the projects the user can't see (such as "openshift") can't be checked
+		return ""
+	}
+	if events, found := d.ImageStreams[i].Status.Tags[tag]; !found || len(events.Items) == 0 {
+		return fmt.Sprintf("tag %v not found in ImageStream %v/%v", tag, namespace, name)
+	}
+	return ""
+}
+
+// isVisibleNamespace returns true if the ImageStreams of the given namespace are in this Data instance
+func (d *Data) isVisibleNamespace(namespace string) bool {
+	_, found := d.indexed().find(ResourceTypeProject, "", namespace)
+	return found
+}
+
+// strategyFromOf returns the reference to the base image of the given build strategy, or nil
+func strategyFromOf(strategy buildapi.BuildStrategy) *kapi.ObjectReference {
+	switch {
+	case strategy.SourceStrategy != nil:
+		return &strategy.SourceStrategy.From
+	case strategy.DockerStrategy != nil:
+		return strategy.DockerStrategy.From
+	case strategy.CustomStrategy != nil:
+		return &strategy.CustomStrategy.From
+	}
+	return nil
+}
+
+// imageKey returns the key of the image referenced by the given object reference, from an object of the given namespace:
+// "namespace/name:tag" for an ImageStream tag (or "namespace/name@id" for an ImageStream image),
+// or the pull spec of a Docker image
+func imageKey(from kapi.ObjectReference, namespace string) string {
+	if len(from.Namespace) > 0 {
+		namespace = from.Namespace
+	}
+	switch from.Kind {
+	case "ImageStreamTag":
+		name, tag := splitImageStreamTag(from.Name)
+		return namespace + "/" + name + ":" + tag
+	case "ImageStreamImage":
+		return namespace + "/" + from.Name
+	case "ImageStream", "ImageRepository":
+		return namespace + "/" + from.Name + ":" + imageapi.DefaultImageTag
+	default:
+		return from.Name
+	}
+}
+
+// splitImageStreamTag returns the name of the ImageStream and the tag of the given ImageStream tag ("name:tag"),
+// with the default tag if there is none
+func splitImageStreamTag(name string) (string, string) {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, imageapi.DefaultImageTag
+}
+
+// repositoryAndTagOf returns the repository of the given image (or ImageStream tag), without its registry
+// (such as "openshift/origin-base"), and its tag (or "@" and its digest), which can be empty
+func repositoryAndTagOf(image string) (string, string) {
+	ref, err := imageapi.ParseDockerImageReference(image)
+	if err != nil {
+		return image, ""
+	}
+	repository := ref.Name
+	if len(ref.Namespace) > 0 && ref.Namespace != "library" {
+		repository = ref.Namespace + "/" + ref.Name
+	}
+	if len(ref.ID) > 0 {
+		return repository, "@" + ref.ID
+	}
+	return repository, ref.Tag
+}
+
+// sortedSpecTagsOf returns the tags of the spec of the given ImageStream, sorted by name
+func sortedSpecTagsOf(is imageapi.ImageStream) []string {
+	tags := []string{}
+	for tag := range is.Spec.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// impactsByDepth sorts the impacts by depth, and then by kind, namespace and name
+type impactsByDepth []Impact
+
+func (i impactsByDepth) Len() int      { return len(i) }
+func (i impactsByDepth) Swap(a, b int) { i[a], i[b] = i[b], i[a] }
+func (i impactsByDepth) Less(a, b int) bool {
+	if i[a].Depth != i[b].Depth {
+		return i[a].Depth < i[b].Depth
+	}
+	return i[a].ImageConsumer.less(i[b].ImageConsumer)
+}
+
+// brokenLinksByObject sorts the broken links by kind, namespace and name
+type brokenLinksByObject []BrokenLink
+
+func (l brokenLinksByObject) Len() int           { return len(l) }
+func (l brokenLinksByObject) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l brokenLinksByObject) Less(i, j int) bool { return l[i].ImageConsumer.less(l[j].ImageConsumer) }
+
+// less compares the consumers by kind, namespace and name
+func (c ImageConsumer) less(other ImageConsumer) bool {
+	if c.Kind != other.Kind {
+		return c.Kind < other.Kind
+	}
+	if c.Namespace != other.Namespace {
+		return c.Namespace < other.Namespace
+	}
+	return c.Name < other.Name
+}
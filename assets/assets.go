@@ -0,0 +1,304 @@
+// Package assets provides the templates and the static files of the dashboard.
+// They are embedded in the binary by "go generate" (in the generated bindata.go file),
+// or read from the disk in dev mode, or if the binary was built without them.
+// An overrides directory can be layered over them, to customize the dashboard.
+package assets
+
+//go:generate go run generate.go
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TemplatesDir is the directory of the templates, relative to the root of the sources
+	TemplatesDir = "templates"
+
+	// PublicDir is the directory of the static files, relative to the root of the sources
+	PublicDir = "public"
+
+	// fingerprintParam is the query parameter with the fingerprint of a static file
+	fingerprintParam = "v"
+
+	// fingerprintedMaxAge is the duration the browsers can cache a fingerprinted static file
+	fingerprintedMaxAge = 365 * 24 * time.Hour
+)
+
+// embeddedFile is a file embedded in the binary, with its gzipped content
+type embeddedFile struct {
+	modTime     int64
+	fingerprint string
+	gzipped     string
+}
+
+// embedded are the files embedded in the binary, by path relative to the root of the sources
+// (such as "templates/layout.tmpl" or "public/assets/...").
+// It is filled by the generated bindata.go file.
+var embedded = map[string]embeddedFile{}
+
+// Embedded returns true if the templates and static files are embedded in the binary
+func Embedded() bool {
+	return len(embedded) > 0
+}
+
+// Assets gives access to the templates and to the static files of the "public" directory
+type Assets struct {
+	// fromDisk is true if the files are read from the disk, instead of the embedded files
+	fromDisk bool
+
+	// dev is true in dev mode: the files are not fingerprinted, and not cached by the browsers
+	dev bool
+
+	publicDir string
+
+	// overridesDir is the directory with the "templates" and "public" files that override the built-in ones, if any
+	overridesDir string
+
+	fingerprintsMutex sync.Mutex
+	fingerprints      map[string]string
+}
+
+// New returns the Assets of the dashboard: the embedded ones,
+// or the ones from the disk (in the given public directory) in dev mode or if there is no embedded files.
+// The files of the given overrides directory (if any) are used instead of the built-in ones.
+func New(dev bool, publicDir string, overridesDir string) *Assets {
+	return &Assets{
+		fromDisk:     dev || !Embedded(),
+		dev:          dev,
+		publicDir:    publicDir,
+		overridesDir: overridesDir,
+		fingerprints: make(map[string]string),
+	}
+}
+
+// Template returns the content of the template file of the given name (such as "layout.tmpl")
+func (a *Assets) Template(name string) ([]byte, error) {
+	return a.readFile(path.Join(TemplatesDir, name))
+}
+
+// TemplateAsset returns the content of the given template file, relative to the root of the sources
+// (such as "templates/layout.tmpl"). It can be used as the Asset function of the renderer.
+func (a *Assets) TemplateAsset(name string) ([]byte, error) {
+	return a.readFile(name)
+}
+
+// TemplateNames returns the names of the templates files, built-in or from the overrides directory,
+// relative to the root of the sources. It can be used as the AssetNames function of the renderer.
+func (a *Assets) TemplateNames() []string {
+	names := make(map[string]bool)
+	if a.fromDisk {
+		for _, name := range listFiles(".", TemplatesDir) {
+			names[name] = true
+		}
+	} else {
+		for name := range embedded {
+			if strings.HasPrefix(name, TemplatesDir+"/") {
+				names[name] = true
+			}
+		}
+	}
+	if len(a.overridesDir) > 0 {
+		for _, name := range listFiles(a.overridesDir, TemplatesDir) {
+			names[name] = true
+		}
+	}
+
+	sorted := []string{}
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// listFiles returns the names of the files of the given directory (relative to the given root), recursively
+func listFiles(root string, dir string) []string {
+	names := []string{}
+	filepath.Walk(filepath.Join(root, dir), func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, file); err == nil {
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return names
+}
+
+// overridePath returns the path of the file that overrides the given one (relative to the root of the sources),
+// or an empty string if it is not overridden
+func (a *Assets) overridePath(name string) string {
+	if len(a.overridesDir) == 0 {
+		return ""
+	}
+	file := filepath.Join(a.overridesDir, filepath.FromSlash(name))
+	if info, err := os.Stat(file); err != nil || info.IsDir() {
+		return ""
+	}
+	return file
+}
+
+// diskPath returns the path on the disk of the given built-in file, relative to the root of the sources
+func (a *Assets) diskPath(name string) string {
+	if rel := strings.TrimPrefix(name, PublicDir+"/"); rel != name {
+		return filepath.Join(a.publicDir, filepath.FromSlash(rel))
+	}
+	return filepath.FromSlash(name)
+}
+
+// readFile returns the content of the given file, relative to the root of the sources:
+// from the overrides directory, from the disk, or from the embedded files
+func (a *Assets) readFile(name string) ([]byte, error) {
+	if file := a.overridePath(name); len(file) > 0 {
+		return ioutil.ReadFile(file)
+	}
+	if a.fromDisk {
+		return ioutil.ReadFile(a.diskPath(name))
+	}
+
+	file, found := embedded[name]
+	if !found {
+		return nil, fmt.Errorf("File %v not found in the embedded files!", name)
+	}
+	return file.content()
+}
+
+// content returns the content of the embedded file
+func (f embeddedFile) content() ([]byte, error) {
+	reader, err := gzip.NewReader(strings.NewReader(f.gzipped))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// URL returns the URL of the given static file (such as "/assets/jquery/dist/jquery.min.js"),
+// with its fingerprint, so that the browsers can cache it for a long time.
+// In dev mode, or if the file can't be read, it returns the URL without fingerprint.
+func (a *Assets) URL(urlPath string) string {
+	if a.dev {
+		return urlPath
+	}
+
+	fingerprint, err := a.fingerprint(urlPath)
+	if err != nil || len(fingerprint) == 0 {
+		return urlPath
+	}
+	return urlPath + "?" + fingerprintParam + "=" + fingerprint
+}
+
+// fingerprint returns the fingerprint of the given static file: a hash of its content
+func (a *Assets) fingerprint(urlPath string) (string, error) {
+	name := path.Join(PublicDir, urlPath)
+	if !a.fromDisk && len(a.overridePath(name)) == 0 {
+		if file, found := embedded[name]; found {
+			return file.fingerprint, nil
+		}
+		return "", nil
+	}
+
+	a.fingerprintsMutex.Lock()
+	defer a.fingerprintsMutex.Unlock()
+	if fingerprint, found := a.fingerprints[urlPath]; found {
+		return fingerprint, nil
+	}
+
+	content, err := a.readFile(name)
+	if err != nil {
+		return "", err
+	}
+	fingerprint := Fingerprint(content)
+	a.fingerprints[urlPath] = fingerprint
+	return fingerprint, nil
+}
+
+// Fingerprint returns the fingerprint of the given content
+func Fingerprint(content []byte) string {
+	hash := sha1.Sum(content)
+	return hex.EncodeToString(hash[:])[:12]
+}
+
+// ServeHTTP serves the static files (as a negroni middleware), with the cache headers:
+// the fingerprinted URLs can be cached for a long time, while the others have to be revalidated.
+// It calls the next handler if there is no static file for the request.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	if req.Method != "GET" && req.Method != "HEAD" {
+		next(w, req)
+		return
+	}
+
+	urlPath := path.Clean("/" + req.URL.Path)
+	content, modTime, fingerprint, err := a.static(path.Join(PublicDir, urlPath))
+	if err != nil {
+		next(w, req)
+		return
+	}
+
+	switch {
+	case a.dev:
+		w.Header().Set("Cache-Control", "no-cache")
+	case len(fingerprint) > 0 && req.URL.Query().Get(fingerprintParam) == fingerprint:
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(fingerprintedMaxAge.Seconds())))
+	default:
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	if len(fingerprint) > 0 {
+		w.Header().Set("ETag", `"`+fingerprint+`"`)
+		if req.Header.Get("If-None-Match") == `"`+fingerprint+`"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	http.ServeContent(w, req, urlPath, modTime, bytes.NewReader(content))
+}
+
+// static returns the content of the given static file (relative to the root of the sources),
+// with its modification time and its fingerprint (outside of the dev mode)
+func (a *Assets) static(name string) ([]byte, time.Time, string, error) {
+	file := a.overridePath(name)
+	if len(file) == 0 && !a.fromDisk {
+		embeddedFile, found := embedded[name]
+		if !found {
+			return nil, time.Time{}, "", os.ErrNotExist
+		}
+		content, err := embeddedFile.content()
+		return content, time.Unix(embeddedFile.modTime, 0), embeddedFile.fingerprint, err
+	}
+
+	if len(file) == 0 {
+		file = a.diskPath(name)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, time.Time{}, "", err
+	}
+	if info.IsDir() {
+		return nil, time.Time{}, "", os.ErrNotExist
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, time.Time{}, "", err
+	}
+
+	fingerprint := ""
+	if !a.dev {
+		fingerprint = Fingerprint(content)
+	}
+	return content, info.ModTime(), fingerprint, nil
+}
@@ -0,0 +1,96 @@
+//go:build ignore
+// +build ignore
+
+// This program generates the bindata.go file, with the templates and the static files embedded.
+// It is run by "go generate" from the assets directory.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vbehar/openshift-dashboard/assets"
+)
+
+// dirs are the directories (or files) to embed, relative to the root of the sources:
+// the templates, and only the static files used by the templates (with the fonts of their stylesheets),
+// not the sources, docs and examples that bower installs with them
+var dirs = []string{
+	assets.TemplatesDir,
+	"public/assets/bootstrap/dist",
+	"public/assets/font-awesome/css",
+	"public/assets/font-awesome/fonts",
+	"public/assets/jquery/dist",
+	"public/assets/metisMenu/dist",
+	"public/assets/momentjs/min/moment-with-locales.min.js",
+	"public/assets/morrisjs/morris.css",
+	"public/assets/morrisjs/morris.min.js",
+	"public/assets/openshift-dashboard",
+	"public/assets/raphael/raphael-min.js",
+	"public/assets/startbootstrap-sb-admin-2/dist",
+}
+
+func main() {
+	files := make(map[string]os.FileInfo)
+	for _, dir := range dirs {
+		err := filepath.Walk(filepath.Join("..", dir), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(info.Name(), ".") {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !info.IsDir() {
+				files[path] = info
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Failed to list the files of %v: %v", dir, err)
+		}
+	}
+
+	paths := []string{}
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var out bytes.Buffer
+	fmt.Fprintln(&out, "// generated by \"go generate\" from generate.go - DO NOT EDIT")
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "package assets")
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "func init() {")
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read %v: %v", path, err)
+		}
+
+		var gzipped bytes.Buffer
+		writer, _ := gzip.NewWriterLevel(&gzipped, gzip.BestCompression)
+		writer.Write(content)
+		writer.Close()
+
+		name, _ := filepath.Rel("..", path)
+		fmt.Fprintf(&out, "\tembedded[%q] = embeddedFile{modTime: %d, fingerprint: %q, gzipped: %q}\n",
+			filepath.ToSlash(name), files[path].ModTime().Unix(), assets.Fingerprint(content), gzipped.String())
+	}
+	fmt.Fprintln(&out, "}")
+
+	if err := ioutil.WriteFile("bindata.go", out.Bytes(), 0644); err != nil {
+		log.Fatalf("Failed to write bindata.go: %v", err)
+	}
+	log.Printf("Embedded %d files in bindata.go", len(paths))
+}
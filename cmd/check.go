@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vbehar/openshift-dashboard/report"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// exitUnhealthy is the exit code of the "check" command when an application is unhealthy
+	exitUnhealthy = 1
+
+	// exitError is the exit code of the "check" command when the data can't be loaded
+	exitError = 2
+)
+
+// newCheckCommand builds the "check" command, which exits with a non-zero code if an application is unhealthy
+func newCheckCommand(configFile *string) *cobra.Command {
+	var (
+		applications  []string
+		allowDegraded bool
+		snapshotFile  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check that the applications are healthy",
+		Long: `Check that the applications are healthy, to be used as a CI gate or a cron probe.
+
+It exits with the code 0 if all the applications are healthy,
+1 if at least one application is down or degraded,
+and 2 if the data can't be loaded.`,
+		Example: `  openshift-dashboard check
+  openshift-dashboard check --application myapp --allow-degraded`,
+		Run: func(cmd *cobra.Command, args []string) {
+			data, at, err := loadData(*configFile, snapshotFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to load the data: %v\n", err)
+				os.Exit(exitError)
+			}
+
+			r := report.New(data, applications, at)
+			for _, application := range applications {
+				if !r.Has(application) {
+					fmt.Fprintf(os.Stderr, "Unknown application %v!\n", application)
+					os.Exit(exitError)
+				}
+			}
+
+			unhealthy := r.Unhealthy(allowDegraded)
+			if len(unhealthy) == 0 {
+				fmt.Printf("OK: none of the %d applications is unhealthy\n", len(r.Applications))
+				return
+			}
+
+			for _, app := range unhealthy {
+				fmt.Printf("%v is %v: %d/%d pods ready\n", app.Name, app.Health, app.ReadyPods, app.DesiredReplicas)
+			}
+			os.Exit(exitUnhealthy)
+		},
+	}
+	cmd.Flags().StringSliceVarP(&applications, "application", "a", nil, "only check the given applications (default to all)")
+	cmd.Flags().BoolVar(&allowDegraded, "allow-degraded", false, "only fail if an application is down")
+	cmd.Flags().StringVar(&snapshotFile, "snapshot", "", "read the data from the given snapshot file, instead of the API")
+	return cmd
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newCleanupCommand builds the "cleanup" command, which prints the "oc delete" commands of the resources that are probably garbage
+func newCleanupCommand(configFile *string) *cobra.Command {
+	var (
+		projects     []string
+		snapshotFile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Print the commands that delete the unused resources",
+		Long: `Print the "oc delete" commands of the resources that are probably garbage, per project, with the reason as a comment:
+the empty projects, the routes to missing services, the services without route that select no pods,
+the ReplicationControllers of deleted DeploymentConfigs, the BuildConfigs that haven't built for a while,
+the old finished builds, and the ImageStreams no one references.
+The system and infra projects (such as "openshift") are never cleaned up.
+
+Nothing is deleted: review the commands before running them.`,
+		Example: `  openshift-dashboard cleanup
+  openshift-dashboard cleanup --project myproject > cleanup.sh`,
+		Run: func(cmd *cobra.Command, args []string) {
+			data, at, err := loadData(*configFile, snapshotFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to load the data: %v\n", err)
+				os.Exit(1)
+			}
+
+			accepted := make(map[string]bool)
+			for _, project := range projects {
+				accepted[project] = true
+			}
+			namespace := ""
+			for _, candidate := range data.CleanupCandidates(at) {
+				if len(accepted) > 0 && !accepted[candidate.Namespace] {
+					continue
+				}
+				if candidate.Namespace != namespace {
+					if len(namespace) > 0 {
+						fmt.Println()
+					}
+					namespace = candidate.Namespace
+					fmt.Printf("# project %v\n", namespace)
+				}
+				if command := candidate.Command(); len(command) > 0 {
+					fmt.Printf("%v # %v\n", command, candidate.Reason)
+				} else {
+					fmt.Printf("# %v %v: %v\n", candidate.Kind, candidate.Name, candidate.Reason)
+				}
+			}
+		},
+	}
+	cmd.Flags().StringSliceVarP(&projects, "project", "p", nil, "only clean up the given projects (default to all)")
+	cmd.Flags().StringVar(&snapshotFile, "snapshot", "", "read the data from the given snapshot file, instead of the API")
+	return cmd
+}
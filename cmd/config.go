@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vbehar/openshift-dashboard/config"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+)
+
+// newConfigCommand builds the "config" command, with its "check" subcommand
+func newConfigCommand(configFile *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "check [FILE]",
+		Short: "Check the configuration",
+		Long: `Check the configuration file given as argument (or defined by the --config flag),
+with the overrides from the env vars, and print the resulting configuration (without the secrets).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			path := *configFile
+			if len(args) > 0 {
+				path = args[0]
+			}
+			os.Exit(checkConfig(path))
+		},
+	})
+	return cmd
+}
+
+// checkConfig validates the given configuration file, and prints the resulting configuration.
+// It returns the exit code: 0 if the configuration is valid, 1 otherwise.
+func checkConfig(path string) int {
+	conf, err := config.Load(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	content, err := yaml.Marshal(conf.Redacted())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if len(path) > 0 {
+		fmt.Printf("The configuration file %v is valid:\n\n", path)
+	} else {
+		fmt.Printf("No configuration file, the configuration from the env vars is valid:\n\n")
+	}
+	os.Stdout.Write(content)
+	return 0
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/config"
+)
+
+// Snapshot is the data of all the resources at a given time, as written by the "snapshot" command
+type Snapshot struct {
+	Time time.Time `json:"time"`
+	Data *api.Data `json:"data"`
+}
+
+// loadData loads the data of all the resource types from the API, with the configuration from the given file,
+// or reads them from the given snapshot file if any (and groups them with the same configuration).
+// It returns the data, and the time they were retrieved.
+func loadData(configFile string, snapshotFile string) (*api.Data, time.Time, error) {
+	conf, err := config.Load(configFile)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if len(snapshotFile) > 0 {
+		return readSnapshot(snapshotFile, conf.ClientOptions())
+	}
+
+	// no cache: the data are only loaded once
+	clientWrapper, err := api.NewClientWrapper(false, conf.Connection(), conf.ClientOptions())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	data, err := clientWrapper.LoadData(api.ResourceTypeAll...)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, time.Now(), nil
+}
+
+// readSnapshot reads the data from the given snapshot file,
+// with the grouping and the policies of the given options (which are not written in the snapshots)
+func readSnapshot(path string, options api.Options) (*api.Data, time.Time, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(content, snapshot); err != nil {
+		return nil, time.Time{}, fmt.Errorf("Failed to read the snapshot %v: %v", path, err)
+	}
+	if snapshot.Data == nil {
+		return nil, time.Time{}, fmt.Errorf("The snapshot %v has no data!", path)
+	}
+	snapshot.Data.Grouping = options.Grouping
+	snapshot.Data.RoutePolicy = options.RoutePolicy
+	snapshot.Data.CleanupPolicy = options.CleanupPolicy
+	// the applications of the snapshot may have been extracted with other labels
+	snapshot.Data.ExtractApplications()
+	snapshot.Data.BuildIndex()
+	return snapshot.Data, snapshot.Time, nil
+}
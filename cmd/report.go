@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vbehar/openshift-dashboard/report"
+
+	"github.com/spf13/cobra"
+)
+
+// newReportCommand builds the "report" command, which prints the status of the applications
+func newReportCommand(configFile *string) *cobra.Command {
+	var (
+		format       string
+		applications []string
+		snapshotFile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Print the status of the applications",
+		Long: `Print the status of the applications: health, ready pods, last build and deployment, projects and routes.
+
+The report can be printed as a table (for the terminal), as JSON (for the scripts), or as a Markdown table.`,
+		Example: `  openshift-dashboard report
+  openshift-dashboard report --format json --application myapp
+  openshift-dashboard report --format markdown --snapshot snapshot.json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			data, at, err := loadData(*configFile, snapshotFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to load the data: %v\n", err)
+				os.Exit(1)
+			}
+
+			if err := report.New(data, applications, at).Write(os.Stdout, report.Format(format)); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", string(report.FormatTable), "output format: table, json or markdown")
+	cmd.Flags().StringSliceVarP(&applications, "application", "a", nil, "only report on the given applications (default to all)")
+	cmd.Flags().StringVar(&snapshotFile, "snapshot", "", "read the data from the given snapshot file, instead of the API")
+	return cmd
+}
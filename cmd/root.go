@@ -0,0 +1,36 @@
+// Package cmd provides the command-line interface of the dashboard
+package cmd
+
+import (
+	"github.com/vbehar/openshift-dashboard/config"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRootCommand builds the root command of the dashboard, with all its subcommands.
+// Without subcommand, it runs the web server.
+func NewRootCommand() *cobra.Command {
+	var configFile string
+
+	root := &cobra.Command{
+		Use:   "openshift-dashboard",
+		Short: "Dashboard of resources from multiple OpenShift projects",
+		Long: `Dashboard of resources from multiple OpenShift projects.
+
+Without command, it runs the web server (same as the "serve" command).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			serve(configFile)
+		},
+	}
+	root.PersistentFlags().StringVar(&configFile, "config", config.File(), "path of the configuration file (default to the CONFIG_FILE env var)")
+
+	root.AddCommand(
+		newServeCommand(&configFile),
+		newReportCommand(&configFile),
+		newCheckCommand(&configFile),
+		newCleanupCommand(&configFile),
+		newSnapshotCommand(&configFile),
+		newConfigCommand(&configFile),
+	)
+	return root
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/vbehar/openshift-dashboard/config"
+	"github.com/vbehar/openshift-dashboard/web"
+
+	"github.com/spf13/cobra"
+)
+
+// newServeCommand builds the "serve" command, which runs the web server
+func newServeCommand(configFile *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Run the web server",
+		Run: func(cmd *cobra.Command, args []string) {
+			serve(*configFile)
+		},
+	}
+}
+
+// serve runs the web server, with the configuration from the given file
+func serve(configFile string) {
+	conf, err := config.Load(configFile)
+	if err != nil {
+		log.Fatalf("Failed to load the configuration: %v", err)
+	}
+
+	web.RunHttpServer(conf, configFile)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	"github.com/spf13/cobra"
+)
+
+// newSnapshotCommand builds the "snapshot" command, which writes the data of all the resources as JSON
+func newSnapshotCommand(configFile *string) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Write the data of all the resources as JSON",
+		Long: `Write the data of all the resources as JSON, to keep a point-in-time view of the projects.
+
+The snapshot can then be given to the "report", "check" and "cleanup" commands, with the --snapshot flag.`,
+		Example: `  openshift-dashboard snapshot --output snapshot.json
+  openshift-dashboard report --snapshot snapshot.json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			data, at, err := loadData(*configFile, "")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to load the data: %v\n", err)
+				os.Exit(1)
+			}
+
+			removeSecrets(data)
+			content, err := json.MarshalIndent(&Snapshot{Time: at, Data: data}, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+
+			if len(output) == 0 {
+				fmt.Println(string(content))
+				return
+			}
+			if err := writePrivateFile(output, append(content, '\n')); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to write the snapshot: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "path of the file to write (default to the standard output)")
+	return cmd
+}
+
+// removeSecrets removes the secrets from the given data, so they are not written in the snapshot:
+// the private keys of the routes (and the CA certificates of their destinations, which are not used).
+// The certificates of the routes are kept, for the reports.
+func removeSecrets(data *api.Data) {
+	for i, route := range data.Routes {
+		if route.TLS == nil {
+			continue
+		}
+		tls := *route.TLS
+		tls.Key = ""
+		tls.DestinationCACertificate = ""
+		data.Routes[i].TLS = &tls
+	}
+}
+
+// writePrivateFile writes the given content to the file at the given path, only readable by its owner
+// (even if the file already exists)
+func writePrivateFile(path string, content []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,430 @@
+// Package config provides the configuration of the dashboard,
+// read from a YAML (or JSON) file, and overridden by env vars.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/dashboard"
+	"github.com/vbehar/openshift-dashboard/duration"
+	"github.com/vbehar/openshift-dashboard/probes"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	// FileEnvVar is the name of the env var with the path of the configuration file
+	FileEnvVar = "CONFIG_FILE"
+)
+
+// Config is the configuration of the dashboard.
+// Each setting can be overridden by the env var defined in its "env" tag.
+type Config struct {
+	Server        ServerConfig        `json:"server"`
+	Cluster       ClusterConfig       `json:"cluster"`
+	Cache         CacheConfig         `json:"cache"`
+	Refresh       RefreshConfig       `json:"refresh"`
+	Projects      ProjectsConfig      `json:"projects"`
+	Resources     []string            `json:"resources,omitempty" env:"RESOURCES"`
+	Grouping      GroupingConfig      `json:"grouping"`
+	Routes        RoutesConfig        `json:"routes"`
+	Probes        ProbesConfig        `json:"probes"`
+	Cleanup       CleanupConfig       `json:"cleanup"`
+	Branding      BrandingConfig      `json:"branding"`
+	Auth          AuthConfig          `json:"auth"`
+	Actions       ActionsConfig       `json:"actions"`
+	Alerts        AlertsConfig        `json:"alerts"`
+	Notifications NotificationsConfig `json:"notifications"`
+	History       HistoryConfig       `json:"history"`
+
+	// Dashboards are the custom dashboards, displayed at /dashboards/:name
+	// (or as the home page, for the "home" dashboard)
+	Dashboards []dashboard.Definition `json:"dashboards,omitempty"`
+}
+
+// ServerConfig is the configuration of the HTTP server
+type ServerConfig struct {
+	Port int `json:"port" env:"PORT"`
+
+	// PublicDir is the directory of the static files,
+	// used in dev mode or if they are not embedded in the binary
+	PublicDir string `json:"publicDir" env:"PUBLIC_DIR"`
+
+	// Env is the environment the dashboard is running in: "dev" disables the caches
+	// and reloads the templates on each request
+	Env string `json:"env,omitempty" env:"GO_ENV"`
+
+	// PublicURL is the public URL of the dashboard, used for the links in the mails
+	PublicURL string `json:"publicURL,omitempty" env:"DASHBOARD_URL"`
+}
+
+// ClusterConfig describes how to connect to the cluster.
+// If it is empty, the service account's credentials are used when running in a cluster,
+// or the default config files (~/.kube/config) otherwise.
+type ClusterConfig struct {
+	Kubeconfig string `json:"kubeconfig,omitempty" env:"CLUSTER_KUBECONFIG"`
+	Context    string `json:"context,omitempty" env:"CLUSTER_CONTEXT"`
+}
+
+// CacheConfig is the configuration of the caches of the data retrieved from the API.
+// A TTL of 0 disables the cache.
+type CacheConfig struct {
+	ResourcesTTL   duration.Duration `json:"resourcesTTL" env:"CACHE_RESOURCES_TTL"`
+	NamespacesTTL  duration.Duration `json:"namespacesTTL" env:"CACHE_NAMESPACES_TTL"`
+	PermissionsTTL duration.Duration `json:"permissionsTTL" env:"CACHE_PERMISSIONS_TTL"`
+}
+
+// RefreshConfig is the configuration of the loading of the data
+type RefreshConfig struct {
+	// Interval is the interval of the background refresh of the data (for the pages, alerts, notifications, ...)
+	Interval duration.Duration `json:"interval" env:"REFRESH_INTERVAL"`
+
+	// LoadTimeout is the maximum duration to load the data from the API
+	LoadTimeout duration.Duration `json:"loadTimeout" env:"LOAD_TIMEOUT"`
+}
+
+// ProjectsConfig selects the projects displayed by the dashboard, with patterns such as "team-*"
+type ProjectsConfig struct {
+	Include []string `json:"include,omitempty" env:"PROJECTS"`
+	Exclude []string `json:"exclude,omitempty" env:"EXCLUDED_PROJECTS"`
+}
+
+// GroupingConfig describes how the objects are grouped: in applications, and in secondary dimensions
+// (such as the team or the environment), both from the labels of the objects
+type GroupingConfig struct {
+	// ApplicationLabels are the labels of the application, by order of preference (such as "app", then "application")
+	ApplicationLabels []string `json:"applicationLabels" env:"APPLICATION_LABELS"`
+
+	// Dimensions are the secondary dimensions, such as the team or the environment
+	Dimensions []DimensionConfig `json:"dimensions,omitempty"`
+
+	// Environments are the environments, in the order of the promotions of the images (such as "dev", then "prod")
+	Environments []string `json:"environments" env:"ENVIRONMENTS"`
+
+	// EnvironmentDimension is the dimension that gives the environment of the objects, such as "env".
+	// If it is empty, the environment is the suffix of the name of the project, such as "myapp-prod".
+	EnvironmentDimension string `json:"environmentDimension,omitempty" env:"ENVIRONMENT_DIMENSION"`
+}
+
+// DimensionConfig is a secondary dimension of the objects.
+// Its value is the one of the first label an object has, or else the first label its project has.
+type DimensionConfig struct {
+	Name   string   `json:"name"`
+	Title  string   `json:"title,omitempty"`
+	Labels []string `json:"labels"`
+}
+
+// RoutesConfig describes what is expected from the routes
+type RoutesConfig struct {
+	// AllowedDomains are the domains of the hosts of the routes, such as "apps.example.com", or any domain if empty
+	AllowedDomains []string `json:"allowedDomains,omitempty" env:"ROUTES_ALLOWED_DOMAINS"`
+
+	// CertificateExpiryDays is how many days before their expiry the certificates of the routes are flagged
+	CertificateExpiryDays int `json:"certificateExpiryDays" env:"ROUTES_CERTIFICATE_EXPIRY_DAYS"`
+}
+
+// CleanupConfig describes when the resources are considered as unused, in the cleanup report
+type CleanupConfig struct {
+	// StaleBuildConfigDays is how many days a BuildConfig can go without a build before it is reported
+	StaleBuildConfigDays int `json:"staleBuildConfigDays" env:"CLEANUP_STALE_BUILDCONFIG_DAYS"`
+
+	// BuildsRetention is the number of finished builds kept for each BuildConfig, the older ones are reported
+	BuildsRetention int `json:"buildsRetention" env:"CLEANUP_BUILDS_RETENTION"`
+
+	// ExcludeProjects are the patterns of the projects that are never cleaned up, such as the system and infra projects
+	ExcludeProjects []string `json:"excludeProjects" env:"CLEANUP_EXCLUDED_PROJECTS"`
+}
+
+// ProbesConfig is the configuration of the HTTP probes of the routes
+type ProbesConfig struct {
+	Enabled bool `json:"enabled" env:"PROBES_ENABLED"`
+
+	// Interval is the interval between two probes of a route
+	Interval duration.Duration `json:"interval" env:"PROBES_INTERVAL"`
+
+	// Timeout is the timeout of a probe, unless a route defines its own
+	Timeout duration.Duration `json:"timeout" env:"PROBES_TIMEOUT"`
+
+	// Workers is the maximum number of concurrent probes
+	Workers int `json:"workers" env:"PROBES_WORKERS"`
+
+	// Jitter is the maximum random delay before each probe, to spread the probes over time
+	Jitter duration.Duration `json:"jitter" env:"PROBES_JITTER"`
+
+	// Retention is how long the results of the probes are kept (in memory)
+	Retention duration.Duration `json:"retention" env:"PROBES_RETENTION"`
+}
+
+// BrandingConfig is the configuration of the look of the dashboard
+type BrandingConfig struct {
+	// Title is the title of the pages, and the text of the navbar brand
+	Title string `json:"title" env:"DASHBOARD_TITLE"`
+
+	// Logo is the URL of the logo displayed in the navbar, such as "/logo.png" from the overrides directory
+	Logo string `json:"logo,omitempty" env:"DASHBOARD_LOGO"`
+
+	// NavbarColor and NavbarTextColor are the CSS colors of the navbar, such as "#2c3e50" or "white"
+	NavbarColor     string `json:"navbarColor,omitempty" env:"DASHBOARD_NAVBAR_COLOR"`
+	NavbarTextColor string `json:"navbarTextColor,omitempty" env:"DASHBOARD_NAVBAR_TEXT_COLOR"`
+
+	// Stylesheets are the URLs of extra CSS files, loaded after the built-in ones
+	Stylesheets []string `json:"stylesheets,omitempty" env:"DASHBOARD_STYLESHEETS"`
+
+	// Links are extra links displayed in the navbar, such as the team's wiki
+	Links []LinkConfig `json:"links,omitempty"`
+
+	// Footer is the text displayed at the bottom of the pages
+	Footer string `json:"footer,omitempty" env:"DASHBOARD_FOOTER"`
+
+	// OverridesDir is a directory with "templates" and "public" sub-directories,
+	// whose files are used instead of the built-in ones (or in addition to them)
+	OverridesDir string `json:"overridesDir,omitempty" env:"DASHBOARD_OVERRIDES_DIR"`
+}
+
+// LinkConfig is a link displayed in the navbar
+type LinkConfig struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// AuthConfig is the configuration of the authentication of the users
+type AuthConfig struct {
+	// Mode is the authentication mode: "oauth", "proxy", or empty to disable the authentication
+	Mode  string          `json:"mode,omitempty" env:"AUTH_MODE"`
+	OAuth OAuthConfig     `json:"oauth"`
+	Proxy ProxyAuthConfig `json:"proxy"`
+}
+
+// OAuthConfig is the configuration of the OAuth client, used by the "oauth" authentication mode
+type OAuthConfig struct {
+	ClientID     string `json:"clientID" env:"OAUTH_CLIENT_ID"`
+	ClientSecret string `json:"clientSecret,omitempty" env:"OAUTH_CLIENT_SECRET"`
+	RedirectURI  string `json:"redirectURI,omitempty" env:"OAUTH_REDIRECT_URI"`
+	ServerURL    string `json:"serverURL,omitempty" env:"OAUTH_SERVER_URL"`
+}
+
+// ProxyAuthConfig is the configuration of the "proxy" authentication mode
+type ProxyAuthConfig struct {
+	TrustedSources []string `json:"trustedSources" env:"PROXY_TRUSTED_SOURCES"`
+	UserHeader     string   `json:"userHeader" env:"PROXY_USER_HEADER"`
+	GroupsHeader   string   `json:"groupsHeader" env:"PROXY_GROUPS_HEADER"`
+}
+
+// ActionsConfig is the configuration of the mutating actions (start a build, ...)
+type ActionsConfig struct {
+	Enabled      bool   `json:"enabled" env:"ACTIONS_ENABLED"`
+	AuditLogFile string `json:"auditLogFile,omitempty" env:"AUDIT_LOG_FILE"`
+
+	// MaxReplicas is the maximum number of replicas a DeploymentConfig can be scaled to
+	MaxReplicas int `json:"maxReplicas" env:"ACTIONS_MAX_REPLICAS"`
+}
+
+// AlertsConfig is the configuration of the alerting rules
+type AlertsConfig struct {
+	RulesFile string `json:"rulesFile,omitempty" env:"ALERT_RULES_FILE"`
+}
+
+// NotificationsConfig is the configuration of the notifiers
+type NotificationsConfig struct {
+	WebhooksFile string     `json:"webhooksFile,omitempty" env:"WEBHOOKS_FILE"`
+	SMTP         SMTPConfig `json:"smtp"`
+
+	// DigestTime is the time of the daily digest, such as "08:00", or "disabled"
+	DigestTime string `json:"digestTime" env:"DIGEST_TIME"`
+}
+
+// SMTPConfig is the configuration of the SMTP server used to send the mails.
+// The mails are disabled if there is no host.
+type SMTPConfig struct {
+	Host       string   `json:"host,omitempty" env:"SMTP_HOST"`
+	Port       string   `json:"port,omitempty" env:"SMTP_PORT"`
+	TLS        string   `json:"tls" env:"SMTP_TLS"`
+	Username   string   `json:"username,omitempty" env:"SMTP_USERNAME"`
+	Password   string   `json:"password,omitempty" env:"SMTP_PASSWORD"`
+	From       string   `json:"from,omitempty" env:"SMTP_FROM"`
+	Recipients []string `json:"recipients,omitempty" env:"SMTP_RECIPIENTS"`
+}
+
+// HistoryConfig is the configuration of the history store.
+// The history is disabled if there is no directory.
+type HistoryConfig struct {
+	Dir             string            `json:"dir,omitempty" env:"HISTORY_DIR"`
+	Retention       duration.Duration `json:"retention" env:"HISTORY_RETENTION"`
+	DownsampleAfter duration.Duration `json:"downsampleAfter" env:"HISTORY_DOWNSAMPLE_AFTER"`
+}
+
+// Default returns the default configuration
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:      8080,
+			PublicDir: "public",
+		},
+		Cache: CacheConfig{
+			ResourcesTTL:   duration.Duration(5 * time.Minute),
+			NamespacesTTL:  duration.Duration(5 * time.Minute),
+			PermissionsTTL: duration.Duration(1 * time.Minute),
+		},
+		Refresh: RefreshConfig{
+			Interval:    duration.Duration(1 * time.Minute),
+			LoadTimeout: duration.Duration(10 * time.Second),
+		},
+		Grouping: GroupingConfig{
+			ApplicationLabels: []string{api.ApplicationNameLabel},
+			Environments:      append([]string{}, api.DefaultEnvironments...),
+		},
+		Routes: RoutesConfig{
+			CertificateExpiryDays: int(api.DefaultCertificateExpiry.Hours() / 24),
+		},
+		Cleanup: CleanupConfig{
+			StaleBuildConfigDays: int(api.DefaultStaleBuildConfigs.Hours() / 24),
+			BuildsRetention:      api.DefaultBuildsRetention,
+			ExcludeProjects:      append([]string{}, api.DefaultCleanupExcludedProjects...),
+		},
+		Actions: ActionsConfig{
+			MaxReplicas: 20,
+		},
+		Probes: ProbesConfig{
+			Interval:  duration.Duration(1 * time.Minute),
+			Timeout:   duration.Duration(5 * time.Second),
+			Workers:   5,
+			Jitter:    duration.Duration(10 * time.Second),
+			Retention: duration.Duration(24 * time.Hour),
+		},
+		Branding: BrandingConfig{
+			Title: "openshift-dashboard",
+		},
+		Auth: AuthConfig{
+			OAuth: OAuthConfig{
+				ClientID: "openshift-dashboard",
+			},
+			Proxy: ProxyAuthConfig{
+				TrustedSources: []string{"127.0.0.1/32"},
+				UserHeader:     "X-Forwarded-User",
+				GroupsHeader:   "X-Forwarded-Groups",
+			},
+		},
+		Notifications: NotificationsConfig{
+			SMTP: SMTPConfig{
+				TLS: "starttls",
+			},
+			DigestTime: "08:00",
+		},
+		History: HistoryConfig{
+			Retention:       duration.Duration(90 * 24 * time.Hour),
+			DownsampleAfter: duration.Duration(48 * time.Hour),
+		},
+	}
+}
+
+// File returns the path of the configuration file, defined by the CONFIG_FILE env var,
+// or an empty string if there is none
+func File() string {
+	return os.Getenv(FileEnvVar)
+}
+
+// Load reads the configuration from the given (YAML or JSON) file, if any,
+// applies the overrides from the env vars, and validates it
+func Load(path string) (*Config, error) {
+	config := Default()
+
+	if len(path) > 0 {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read the configuration file: %v", err)
+		}
+
+		jsonContent, err := yaml.YAMLToJSON(content)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the configuration file %v: %v", path, err)
+		}
+		if problems := unknownFields(jsonContent, config); len(problems) > 0 {
+			return nil, &ValidationError{Problems: problems}
+		}
+		if err := yaml.Unmarshal(content, config); err != nil {
+			return nil, fmt.Errorf("Failed to parse the configuration file %v: %v", path, err)
+		}
+	}
+
+	if problems := applyEnv(config, os.Getenv); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// IsDevEnv returns true if we are running in "dev" env
+func (c *Config) IsDevEnv() bool {
+	return strings.ToLower(c.Server.Env) == "dev"
+}
+
+// Connection returns how to connect to the cluster
+func (c *Config) Connection() api.Connection {
+	return api.Connection{
+		Kubeconfig: c.Cluster.Kubeconfig,
+		Context:    c.Cluster.Context,
+	}
+}
+
+// ClientOptions returns the options of the ClientWrapper
+func (c *Config) ClientOptions() api.Options {
+	options := api.Options{
+		ResourcesCacheTTL:   time.Duration(c.Cache.ResourcesTTL),
+		NamespacesCacheTTL:  time.Duration(c.Cache.NamespacesTTL),
+		PermissionsCacheTTL: time.Duration(c.Cache.PermissionsTTL),
+		LoadTimeout:         time.Duration(c.Refresh.LoadTimeout),
+		SnapshotMaxAge:      time.Duration(c.Refresh.Interval),
+		IncludedProjects:    c.Projects.Include,
+		ExcludedProjects:    c.Projects.Exclude,
+	}
+	for _, resource := range c.Resources {
+		options.ResourceTypes = append(options.ResourceTypes, api.ResourceType(resource))
+	}
+	options.Grouping.ApplicationLabels = c.Grouping.ApplicationLabels
+	options.Grouping.Environments = c.Grouping.Environments
+	options.Grouping.EnvironmentDimension = c.Grouping.EnvironmentDimension
+	for _, dimension := range c.Grouping.Dimensions {
+		options.Grouping.Dimensions = append(options.Grouping.Dimensions, api.Dimension{
+			Name:   dimension.Name,
+			Title:  dimension.Title,
+			Labels: dimension.Labels,
+		})
+	}
+	options.RoutePolicy.AllowedDomains = c.Routes.AllowedDomains
+	options.RoutePolicy.CertificateExpiry = time.Duration(c.Routes.CertificateExpiryDays) * 24 * time.Hour
+	options.CleanupPolicy.StaleBuildConfigs = time.Duration(c.Cleanup.StaleBuildConfigDays) * 24 * time.Hour
+	options.CleanupPolicy.BuildsRetention = c.Cleanup.BuildsRetention
+	options.CleanupPolicy.ExcludedProjects = append([]string{}, c.Cleanup.ExcludeProjects...)
+	return options
+}
+
+// ProberConfig returns the configuration of the probes of the routes
+func (c *Config) ProberConfig() probes.Config {
+	return probes.Config{
+		Interval:  time.Duration(c.Probes.Interval),
+		Timeout:   time.Duration(c.Probes.Timeout),
+		Workers:   c.Probes.Workers,
+		Jitter:    time.Duration(c.Probes.Jitter),
+		Retention: time.Duration(c.Probes.Retention),
+	}
+}
+
+// Redacted returns a copy of the configuration, without the secrets
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	if len(redacted.Auth.OAuth.ClientSecret) > 0 {
+		redacted.Auth.OAuth.ClientSecret = "<redacted>"
+	}
+	if len(redacted.Notifications.SMTP.Password) > 0 {
+		redacted.Notifications.SMTP.Password = "<redacted>"
+	}
+	return &redacted
+}
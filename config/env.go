@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/duration"
+)
+
+// durationType is the type of the durations, which are parsed from strings such as "10m"
+var durationType = reflect.TypeOf(duration.Duration(0))
+
+// applyEnv overrides the settings of the given configuration with the values of the env vars
+// defined in the "env" tags of the fields. It returns the problems with the values of the env vars.
+func applyEnv(config *Config, getenv func(string) string) []string {
+	return applyEnvToStruct(reflect.ValueOf(config).Elem(), getenv)
+}
+
+// applyEnvToStruct overrides the fields of the given struct, recursively
+func applyEnvToStruct(value reflect.Value, getenv func(string) string) []string {
+	problems := []string{}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() == reflect.Struct {
+			problems = append(problems, applyEnvToStruct(field, getenv)...)
+			continue
+		}
+
+		name := value.Type().Field(i).Tag.Get("env")
+		if len(name) == 0 {
+			continue
+		}
+		envValue := getenv(name)
+		if len(envValue) == 0 {
+			continue
+		}
+		if err := setValue(field, envValue); err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", name, err))
+		}
+	}
+	return problems
+}
+
+// setValue sets the given field from the string value of an env var
+func setValue(field reflect.Value, value string) error {
+	switch {
+	case field.Type() == durationType:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, it should be such as \"10m\"", value)
+		}
+		field.SetInt(int64(duration))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q, it should be true or false", value)
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		// a comma-separated list
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %v", field.Type())
+	}
+	return nil
+}
+
+// unknownFields returns the settings of the given JSON content which are not fields of the given configuration,
+// such as a typo in the configuration file
+func unknownFields(content []byte, config *Config) []string {
+	var settings map[string]interface{}
+	if err := json.Unmarshal(content, &settings); err != nil {
+		// not an object: the unmarshalling will report it
+		return nil
+	}
+	problems := unknownFieldsOf(settings, reflect.TypeOf(*config), "")
+	sort.Strings(problems)
+	return problems
+}
+
+// unknownFieldsOf returns the keys of the given settings which are not fields of the given struct type, recursively
+func unknownFieldsOf(settings map[string]interface{}, structType reflect.Type, prefix string) []string {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		fields[name] = field.Type
+	}
+
+	problems := []string{}
+	for key, value := range settings {
+		fieldType, found := fields[key]
+		if !found {
+			problems = append(problems, fmt.Sprintf("%v%v: unknown setting", prefix, key))
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok && fieldType.Kind() == reflect.Struct {
+			problems = append(problems, unknownFieldsOf(nested, fieldType, prefix+key+".")...)
+		}
+	}
+	return problems
+}
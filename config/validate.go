@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/alerts"
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/dashboard"
+	"github.com/vbehar/openshift-dashboard/notify"
+)
+
+// cssColorRegexp matches the CSS colors that can be used in the branding:
+// hexadecimal colors (such as "#2c3e50") or color names (such as "white")
+var cssColorRegexp = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|[a-zA-Z]+)$`)
+
+// domainRegexp matches the DNS domains (such as "apps.example.com"), optionally with a wildcard (such as "*.example.com")
+var domainRegexp = regexp.MustCompile(`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// ValidationError lists all the problems found in the configuration
+type ValidationError struct {
+	Problems []string
+}
+
+// Error returns the problems, one per line
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid configuration:\n  - %v", strings.Join(e.Problems, "\n  - "))
+}
+
+// Validate checks the configuration, including the alerting rules and webhooks files it references.
+// It returns a ValidationError with all the problems found, or nil if the configuration is valid.
+func (c *Config) Validate() error {
+	problems := []string{}
+	problem := func(setting string, format string, args ...interface{}) {
+		problems = append(problems, setting+": "+fmt.Sprintf(format, args...))
+	}
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		problem("server.port", "%v is not a valid port, it should be between 1 and 65535", c.Server.Port)
+	}
+	if len(c.Server.PublicDir) == 0 {
+		problem("server.publicDir", "missing directory of the static files")
+	}
+
+	if c.Cache.ResourcesTTL < 0 {
+		problem("cache.resourcesTTL", "the TTL can't be negative")
+	}
+	if c.Cache.NamespacesTTL < 0 {
+		problem("cache.namespacesTTL", "the TTL can't be negative")
+	}
+	if c.Cache.PermissionsTTL < 0 {
+		problem("cache.permissionsTTL", "the TTL can't be negative")
+	}
+	if c.Refresh.Interval <= 0 {
+		problem("refresh.interval", "the interval should be positive, such as \"1m\"")
+	}
+	if c.Refresh.LoadTimeout <= 0 {
+		problem("refresh.loadTimeout", "the timeout should be positive, such as \"10s\"")
+	}
+
+	for _, pattern := range c.Projects.Include {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problem("projects.include", "invalid pattern %q", pattern)
+		}
+	}
+	for _, pattern := range c.Projects.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problem("projects.exclude", "invalid pattern %q", pattern)
+		}
+	}
+
+	known := make(map[string]bool)
+	for _, resourceType := range api.ResourceTypeAll {
+		known[string(resourceType)] = true
+	}
+	for _, resource := range c.Resources {
+		if !known[resource] {
+			problem("resources", "unknown resource type %q", resource)
+		}
+	}
+
+	if len(c.Grouping.ApplicationLabels) == 0 {
+		problem("grouping.applicationLabels", "missing label of the applications, such as \"application\"")
+	}
+	for _, label := range c.Grouping.ApplicationLabels {
+		if !isLabelKey(label) {
+			problem("grouping.applicationLabels", "invalid label %q", label)
+		}
+	}
+	dimensions := make(map[string]bool)
+	for i, dimension := range c.Grouping.Dimensions {
+		setting := fmt.Sprintf("grouping.dimensions[%d]", i)
+		if len(dimension.Name) == 0 {
+			problem(setting, "missing name of the dimension, such as \"env\"")
+		} else if dimensions[dimension.Name] {
+			problem(setting, "duplicate dimension %q", dimension.Name)
+		}
+		dimensions[dimension.Name] = true
+		if len(dimension.Labels) == 0 {
+			problem(setting, "missing labels of the dimension, such as [\"env\", \"environment\"]")
+		}
+		for _, label := range dimension.Labels {
+			if !isLabelKey(label) {
+				problem(setting, "invalid label %q", label)
+			}
+		}
+	}
+
+	if len(c.Grouping.Environments) == 0 {
+		problem("grouping.environments", "missing environments, such as [\"dev\", \"prod\"]")
+	}
+	if dimension := c.Grouping.EnvironmentDimension; len(dimension) > 0 && !dimensions[dimension] {
+		problem("grouping.environmentDimension", "unknown dimension %q", dimension)
+	}
+
+	for _, domain := range c.Routes.AllowedDomains {
+		if !domainRegexp.MatchString(domain) {
+			problem("routes.allowedDomains", "invalid domain %q, it should be such as \"apps.example.com\"", domain)
+		}
+	}
+	if c.Routes.CertificateExpiryDays <= 0 {
+		problem("routes.certificateExpiryDays", "should be a positive number of days, such as 30")
+	}
+
+	if c.Cleanup.StaleBuildConfigDays <= 0 {
+		problem("cleanup.staleBuildConfigDays", "should be a positive number of days, such as 90")
+	}
+	if c.Cleanup.BuildsRetention <= 0 {
+		problem("cleanup.buildsRetention", "should be a positive number of builds, such as 5")
+	}
+	for _, pattern := range c.Cleanup.ExcludeProjects {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problem("cleanup.excludeProjects", "invalid pattern %q", pattern)
+		}
+	}
+
+	if c.Probes.Enabled {
+		if len(c.Routes.AllowedDomains) == 0 {
+			problem("probes.enabled", "the probes require the allowed domains of the routes (routes.allowedDomains), so the projects can't probe the internal hosts of the cluster")
+		}
+		if c.Probes.Interval <= 0 {
+			problem("probes.interval", "should be a positive duration, such as \"1m\"")
+		}
+		if c.Probes.Timeout <= 0 || c.Probes.Timeout > c.Probes.Interval {
+			problem("probes.timeout", "should be a positive duration, shorter than the interval, such as \"5s\"")
+		}
+		if c.Probes.Workers <= 0 {
+			problem("probes.workers", "should be a positive number of concurrent probes, such as 5")
+		}
+		if c.Probes.Jitter < 0 || c.Probes.Jitter >= c.Probes.Interval {
+			problem("probes.jitter", "should be a duration shorter than the interval, such as \"10s\"")
+		}
+		if c.Probes.Retention < c.Probes.Interval {
+			problem("probes.retention", "should be a duration longer than the interval, such as \"24h\"")
+		}
+	}
+
+	if color := c.Branding.NavbarColor; len(color) > 0 && !cssColorRegexp.MatchString(color) {
+		problem("branding.navbarColor", "invalid color %q, it should be such as \"#2c3e50\" or \"white\"", color)
+	}
+	if color := c.Branding.NavbarTextColor; len(color) > 0 && !cssColorRegexp.MatchString(color) {
+		problem("branding.navbarTextColor", "invalid color %q, it should be such as \"#2c3e50\" or \"white\"", color)
+	}
+	for i, link := range c.Branding.Links {
+		if len(link.Title) == 0 || len(link.URL) == 0 {
+			problem(fmt.Sprintf("branding.links[%d]", i), "a link should have a title and an URL")
+		}
+	}
+	if dir := c.Branding.OverridesDir; len(dir) > 0 {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			problem("branding.overridesDir", "%v is not a directory", dir)
+		}
+	}
+
+	switch c.Auth.Mode {
+	case "":
+	case "oauth":
+		if len(c.Auth.OAuth.ClientSecret) == 0 {
+			problem("auth.oauth.clientSecret", "missing secret of the OAuth client")
+		}
+		if len(c.Auth.OAuth.RedirectURI) == 0 {
+			problem("auth.oauth.redirectURI", "missing redirect URI of the OAuth client")
+		}
+	case "proxy":
+		for _, source := range c.Auth.Proxy.TrustedSources {
+			if _, _, err := net.ParseCIDR(source); err != nil && net.ParseIP(source) == nil {
+				problem("auth.proxy.trustedSources", "invalid IP or CIDR %q", source)
+			}
+		}
+	default:
+		problem("auth.mode", "unknown authentication mode %q, it should be \"oauth\" or \"proxy\"", c.Auth.Mode)
+	}
+
+	if c.Actions.Enabled && len(c.Auth.Mode) == 0 {
+		problem("actions.enabled", "the actions require an authentication mode (auth.mode), they would be performed by anyone with the service account")
+	}
+	if c.Actions.MaxReplicas <= 0 {
+		problem("actions.maxReplicas", "should be a positive number of replicas, such as 20")
+	}
+
+	if len(c.Alerts.RulesFile) > 0 {
+		if _, err := alerts.LoadRules(c.Alerts.RulesFile); err != nil {
+			problem("alerts.rulesFile", "%v", err)
+		}
+	}
+
+	if len(c.Notifications.WebhooksFile) > 0 {
+		if _, err := notify.LoadWebhooks(c.Notifications.WebhooksFile); err != nil {
+			problem("notifications.webhooksFile", "%v", err)
+		}
+	}
+	if smtp := c.Notifications.SMTP; len(smtp.Host) > 0 {
+		if len(smtp.From) == 0 {
+			problem("notifications.smtp.from", "missing sender address")
+		}
+		switch notify.TLSMode(smtp.TLS) {
+		case notify.TLSModeStartTLS, notify.TLSModeTLS, notify.TLSModeNone:
+		default:
+			problem("notifications.smtp.tls", "unknown TLS mode %q, it should be \"starttls\", \"tls\" or \"none\"", smtp.TLS)
+		}
+	}
+	if c.Notifications.DigestTime != "disabled" {
+		if _, err := time.Parse("15:04", c.Notifications.DigestTime); err != nil {
+			problem("notifications.digestTime", "invalid time %q, it should be such as \"08:00\" or \"disabled\"", c.Notifications.DigestTime)
+		}
+	}
+
+	if len(c.History.Dir) > 0 {
+		if c.History.Retention <= 0 {
+			problem("history.retention", "the retention should be positive, such as \"2160h\"")
+		}
+		if c.History.DownsampleAfter <= 0 {
+			problem("history.downsampleAfter", "the delay should be positive, such as \"48h\"")
+		}
+	}
+
+	if err := dashboard.Validate(c.Dashboards); err != nil {
+		problem("dashboards", "%v", err)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// isLabelKey returns true if the given string is a valid label key, such as "app" or "example.com/team"
+func isLabelKey(key string) bool {
+	// a single key is an "exists" requirement, which validates the key
+	_, err := api.ParseLabelSelector(key)
+	return err == nil && len(key) > 0 && !strings.ContainsAny(key, ",!=() ")
+}
@@ -0,0 +1,210 @@
+// Package dashboard provides the custom dashboards, defined in the configuration as lists of panels
+package dashboard
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/vbehar/openshift-dashboard/api"
+)
+
+// PanelType describes the possible types of panels
+type PanelType string
+
+const (
+	// PanelTypeCounter displays the number of objects
+	PanelTypeCounter PanelType = "counter"
+
+	// PanelTypeTable displays the objects, or the number of objects per group
+	PanelTypeTable PanelType = "table"
+
+	// PanelTypeDonut displays the number of objects per group, in a donut chart
+	PanelTypeDonut PanelType = "donut"
+
+	// PanelTypeTimeseries displays the number of objects created per day (and per group)
+	PanelTypeTimeseries PanelType = "timeseries"
+
+	// PanelTypeAlerts displays the active alerts
+	PanelTypeAlerts PanelType = "alerts"
+)
+
+const (
+	// GroupByNamespace groups the objects by namespace (project)
+	GroupByNamespace = "namespace"
+
+	// GroupByApplication groups the objects by application
+	GroupByApplication = "application"
+
+	// GroupByStatus groups the objects by status (phase of the pods and builds, health of the deploymentconfigs, ...)
+	GroupByStatus = "status"
+
+	// GroupByLabelPrefix is the prefix to group the objects by the value of a label, such as "label:tier"
+	GroupByLabelPrefix = "label:"
+)
+
+const (
+	// HomeDashboard is the name of the dashboard used as the home page, if it is defined
+	HomeDashboard = "home"
+
+	// defaultDays is the default number of days displayed by a timeseries panel
+	defaultDays = 14
+)
+
+// nameRegexp matches the valid names of dashboards, which are used in the URLs
+var nameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Definition is the definition of a dashboard, displayed at /dashboards/:name
+type Definition struct {
+	// Name identifies the dashboard in its URL
+	Name string `json:"name"`
+
+	// Title is displayed in the page header, and defaults to the name
+	Title string `json:"title,omitempty"`
+
+	Panels []PanelDefinition `json:"panels"`
+}
+
+// PanelDefinition is the definition of a panel of a dashboard
+type PanelDefinition struct {
+	Type PanelType `json:"type"`
+
+	// Title is displayed in the panel heading, and defaults to a description of the objects
+	Title string `json:"title,omitempty"`
+
+	// Resource is the type of the objects displayed by the panel (not used by the alerts panels)
+	Resource api.ResourceType `json:"resource,omitempty"`
+
+	// Namespace restricts the panel to the namespaces (projects) matching the pattern, such as "team-*"
+	Namespace string `json:"namespace,omitempty"`
+
+	// Application restricts the panel to the objects of the given application
+	Application string `json:"application,omitempty"`
+
+	// Selector restricts the panel to the objects matching the label selector, such as "tier=frontend,env!=dev"
+	Selector string `json:"selector,omitempty"`
+
+	// GroupBy is "namespace", "application", "status" or "label:<key>", used by the tables, donuts and timeseries
+	GroupBy string `json:"groupBy,omitempty"`
+
+	// Width is the width of the panel, from 1 to 12 columns
+	Width int `json:"width,omitempty"`
+
+	// Days is the number of days displayed by a timeseries panel
+	Days int `json:"days,omitempty"`
+}
+
+// panelResourceTypes are the resource types that can be displayed by the panels
+var panelResourceTypes = []api.ResourceType{
+	api.ResourceTypeApplication,
+	api.ResourceTypeProject,
+	api.ResourceTypeRoute,
+	api.ResourceTypeService,
+	api.ResourceTypeEndpoints,
+	api.ResourceTypePod,
+	api.ResourceTypeImageStream,
+	api.ResourceTypeBuildConfig,
+	api.ResourceTypeBuild,
+	api.ResourceTypeDeploymentConfig,
+	api.ResourceTypeReplicationController,
+	api.ResourceTypeEvent,
+}
+
+// Validate checks the given definitions of dashboards, and returns the first problem found
+func Validate(definitions []Definition) error {
+	names := make(map[string]bool)
+	for _, definition := range definitions {
+		if err := definition.validate(); err != nil {
+			return err
+		}
+		if names[definition.Name] {
+			return fmt.Errorf("Duplicate dashboard %v!", definition.Name)
+		}
+		names[definition.Name] = true
+	}
+	return nil
+}
+
+// validate checks that the definition of the dashboard is valid
+func (d *Definition) validate() error {
+	if !nameRegexp.MatchString(d.Name) {
+		return fmt.Errorf("Invalid name %q for a dashboard: it should only contain lowercase letters, digits and dashes!", d.Name)
+	}
+	if len(d.Panels) == 0 {
+		return fmt.Errorf("Dashboard %v has no panels!", d.Name)
+	}
+	for i, panel := range d.Panels {
+		if err := panel.validate(); err != nil {
+			return fmt.Errorf("Invalid panel %d of dashboard %v: %v", i+1, d.Name, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that the definition of the panel is valid
+func (p *PanelDefinition) validate() error {
+	switch p.Type {
+	case PanelTypeCounter, PanelTypeTable, PanelTypeDonut, PanelTypeTimeseries:
+		if !isPanelResourceType(p.Resource) {
+			return fmt.Errorf("unknown resource type %q", p.Resource)
+		}
+	case PanelTypeAlerts:
+	default:
+		return fmt.Errorf("unknown type %q, it should be counter, table, donut, timeseries or alerts", p.Type)
+	}
+
+	if _, err := path.Match(p.Namespace, ""); err != nil {
+		return fmt.Errorf("invalid namespace pattern %q", p.Namespace)
+	}
+	if _, err := api.ParseLabelSelector(p.Selector); err != nil {
+		return fmt.Errorf("invalid label selector %q", p.Selector)
+	}
+
+	switch {
+	case len(p.GroupBy) == 0:
+		if p.Type == PanelTypeDonut {
+			return fmt.Errorf("a donut needs a groupBy field")
+		}
+	case p.GroupBy == GroupByNamespace, p.GroupBy == GroupByApplication, p.GroupBy == GroupByStatus:
+	case strings.HasPrefix(p.GroupBy, GroupByLabelPrefix) && len(p.GroupBy) > len(GroupByLabelPrefix):
+	default:
+		return fmt.Errorf("invalid groupBy %q, it should be namespace, application, status or label:<key>", p.GroupBy)
+	}
+
+	if p.Width < 0 || p.Width > 12 {
+		return fmt.Errorf("invalid width %d, it should be between 1 and 12", p.Width)
+	}
+	if p.Days < 0 {
+		return fmt.Errorf("the number of days can't be negative")
+	}
+	return nil
+}
+
+// isPanelResourceType returns true if the given resource type can be displayed by the panels
+func isPanelResourceType(resourceType api.ResourceType) bool {
+	for _, rt := range panelResourceTypes {
+		if rt == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the definition of the dashboard of the given name
+func Find(definitions []Definition, name string) (Definition, bool) {
+	for _, definition := range definitions {
+		if definition.Name == name {
+			return definition, true
+		}
+	}
+	return Definition{}, false
+}
+
+// DisplayTitle returns the title of the dashboard, or its name
+func (d Definition) DisplayTitle() string {
+	if len(d.Title) > 0 {
+		return d.Title
+	}
+	return d.Name
+}
@@ -0,0 +1,119 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// Object is an object displayed by a panel, whatever its type
+type Object struct {
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace,omitempty"`
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Status    string            `json:"status,omitempty"`
+	Created   time.Time         `json:"created"`
+
+	// Application is the application of the object, from its labels (or its name, for an application)
+	Application string `json:"application,omitempty"`
+}
+
+// newObject returns the Object of the given kind, with the given metadata and status
+func newObject(kind string, meta kapi.ObjectMeta, status string) Object {
+	return Object{
+		Kind:      kind,
+		Namespace: meta.Namespace,
+		Name:      meta.Name,
+		Labels:    meta.Labels,
+		Status:    status,
+		Created:   meta.CreationTimestamp.Time,
+	}
+}
+
+// objectsOf returns the objects of the given resource type
+func objectsOf(data *api.Data, resourceType api.ResourceType) []Object {
+	objects := []Object{}
+	switch resourceType {
+	case api.ResourceTypeApplication:
+		for _, app := range data.Applications {
+			objects = append(objects, Object{
+				Kind:        "Application",
+				Name:        app.Name(),
+				Application: app.Name(),
+				Status:      string(data.ApplicationHealthOf(app.Name(), "")),
+			})
+		}
+	case api.ResourceTypeProject:
+		for _, project := range data.Projects {
+			object := newObject("Project", project.ObjectMeta, string(project.Status.Phase))
+			// a project is in its own namespace, for the namespace filter and the groupBy field
+			object.Namespace = project.Name
+			objects = append(objects, object)
+		}
+	case api.ResourceTypeRoute:
+		for _, route := range data.Routes {
+			objects = append(objects, newObject("Route", route.ObjectMeta, ""))
+		}
+	case api.ResourceTypeService:
+		for _, service := range data.Services {
+			objects = append(objects, newObject("Service", service.ObjectMeta, ""))
+		}
+	case api.ResourceTypeEndpoints:
+		for _, endpoints := range data.Endpoints {
+			status := "NotReady"
+			for _, subset := range endpoints.Subsets {
+				if len(subset.Addresses) > 0 {
+					status = "Ready"
+				}
+			}
+			objects = append(objects, newObject("Endpoints", endpoints.ObjectMeta, status))
+		}
+	case api.ResourceTypePod:
+		for _, pod := range data.Pods {
+			objects = append(objects, newObject("Pod", pod.ObjectMeta, string(pod.Status.Phase)))
+		}
+	case api.ResourceTypeImageStream:
+		for _, is := range data.ImageStreams {
+			objects = append(objects, newObject("ImageStream", is.ObjectMeta, ""))
+		}
+	case api.ResourceTypeBuildConfig:
+		for _, bc := range data.BuildConfigs {
+			// the status of the latest build
+			status := ""
+			var latest time.Time
+			for _, build := range data.BuildsOf(bc) {
+				if build.CreationTimestamp.Time.After(latest) {
+					latest = build.CreationTimestamp.Time
+					status = string(build.Status.Phase)
+				}
+			}
+			objects = append(objects, newObject("BuildConfig", bc.ObjectMeta, status))
+		}
+	case api.ResourceTypeBuild:
+		for _, build := range data.Builds {
+			objects = append(objects, newObject("Build", build.ObjectMeta, string(build.Status.Phase)))
+		}
+	case api.ResourceTypeDeploymentConfig:
+		for _, dc := range data.DeploymentConfigs {
+			objects = append(objects, newObject("DeploymentConfig", dc.ObjectMeta, string(data.HealthOf(dc))))
+		}
+	case api.ResourceTypeReplicationController:
+		for _, rc := range data.ReplicationControllers {
+			objects = append(objects, newObject("ReplicationController", rc.ObjectMeta, string(api.DeploymentStatusOf(rc))))
+		}
+	case api.ResourceTypeEvent:
+		for _, event := range data.Events {
+			objects = append(objects, newObject("Event", event.ObjectMeta, event.Reason))
+		}
+	}
+
+	for i := range objects {
+		if objects[i].Kind != "Application" {
+			objects[i].Application = data.ApplicationOf(objects[i].Labels)
+		}
+	}
+	return objects
+}
@@ -0,0 +1,284 @@
+package dashboard
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/alerts"
+	"github.com/vbehar/openshift-dashboard/api"
+
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+const (
+	// noValue is the group of the objects without value for the groupBy field
+	noValue = "(none)"
+)
+
+// Dashboard is a dashboard, with the content of its panels
+type Dashboard struct {
+	Definition
+	Panels []Panel
+}
+
+// Panel is a panel of a dashboard, with its content
+type Panel struct {
+	PanelDefinition
+
+	// ID identifies the panel in the page, for the charts
+	ID string
+
+	// Count is the number of matching objects (or alerts)
+	Count int
+
+	// Objects are the matching objects, from the most recent one
+	Objects []Object
+
+	// Groups are the number of objects per group, from the largest one (if there is a groupBy field)
+	Groups []Group
+
+	// Series are the number of objects created per day (and per group), for the timeseries panels
+	Series     []map[string]interface{}
+	SeriesKeys []string
+
+	// Alerts are the matching active alerts, for the alerts panels
+	Alerts []alerts.Alert
+}
+
+// Group is the number of objects of a group, such as a status
+type Group struct {
+	Label string `json:"label"`
+	Value int    `json:"value"`
+}
+
+// Evaluate returns the dashboard with the content of its panels, from the given data and active alerts
+func (d Definition) Evaluate(data *api.Data, activeAlerts []alerts.Alert, now time.Time) *Dashboard {
+	dashboard := &Dashboard{
+		Definition: d,
+		Panels:     []Panel{},
+	}
+	for i, definition := range d.Panels {
+		panel := definition.evaluate(data, activeAlerts, now)
+		panel.ID = fmt.Sprintf("panel-%d", i+1)
+		dashboard.Panels = append(dashboard.Panels, panel)
+	}
+	return dashboard
+}
+
+// evaluate returns the panel with its content
+func (p PanelDefinition) evaluate(data *api.Data, activeAlerts []alerts.Alert, now time.Time) Panel {
+	panel := Panel{
+		PanelDefinition: p,
+	}
+	if len(panel.Title) == 0 {
+		panel.Title = p.defaultTitle()
+	}
+
+	if len(p.Application) > 0 {
+		data = data.ForApplication(p.Application)
+	}
+	if len(p.Namespace) > 0 && p.Resource == api.ResourceTypeApplication {
+		// the applications have no namespace: they are evaluated on the objects of the namespaces of the panel
+		data = data.ForNamespaces(func(namespace string) bool {
+			matched, _ := path.Match(p.Namespace, namespace)
+			return matched
+		})
+	}
+
+	if p.Type == PanelTypeAlerts {
+		panel.Alerts = p.filterAlerts(data, activeAlerts)
+		panel.Count = len(panel.Alerts)
+		return panel
+	}
+
+	panel.Objects = p.filterObjects(objectsOf(data, p.Resource))
+	sort.Sort(objectsByCreation(panel.Objects))
+	panel.Count = len(panel.Objects)
+	if len(p.GroupBy) > 0 {
+		panel.Groups = groupsOf(panel.Objects, p.groupOf)
+	}
+	if p.Type == PanelTypeTimeseries {
+		panel.Series, panel.SeriesKeys = p.series(panel.Objects, now)
+	}
+	return panel
+}
+
+// filterObjects returns the objects matching the namespace and the label selector of the panel
+func (p PanelDefinition) filterObjects(objects []Object) []Object {
+	selector, err := api.ParseLabelSelector(p.Selector)
+	if err != nil {
+		// already validated
+		return []Object{}
+	}
+
+	filtered := []Object{}
+	for _, object := range objects {
+		// the applications have no namespace, their data have already been filtered
+		if len(p.Namespace) > 0 && object.Kind != "Application" {
+			if matched, _ := path.Match(p.Namespace, object.Namespace); !matched {
+				continue
+			}
+		}
+		if !selector.Matches(labels.Set(object.Labels)) {
+			continue
+		}
+		filtered = append(filtered, object)
+	}
+	return filtered
+}
+
+// filterAlerts returns the alerts in the namespace of the panel,
+// and on the objects of the given data if the panel is restricted to an application
+func (p PanelDefinition) filterAlerts(data *api.Data, activeAlerts []alerts.Alert) []alerts.Alert {
+	var keys map[string]bool
+	if len(p.Application) > 0 {
+		keys = make(map[string]bool)
+		for _, resourceType := range panelResourceTypes {
+			for _, object := range objectsOf(data, resourceType) {
+				keys[object.Kind+"/"+object.Namespace+"/"+object.Name] = true
+			}
+		}
+	}
+
+	filtered := []alerts.Alert{}
+	for _, alert := range activeAlerts {
+		if len(p.Namespace) > 0 {
+			if matched, _ := path.Match(p.Namespace, alert.Namespace); !matched {
+				continue
+			}
+		}
+		if keys != nil && !keys[alert.Kind+"/"+alert.Namespace+"/"+alert.Name] {
+			continue
+		}
+		filtered = append(filtered, alert)
+	}
+	return filtered
+}
+
+// groupOf returns the group of the given object, for the groupBy field of the panel
+func (p PanelDefinition) groupOf(object Object) string {
+	value := ""
+	switch {
+	case p.GroupBy == GroupByNamespace:
+		value = object.Namespace
+	case p.GroupBy == GroupByApplication:
+		value = object.Application
+	case p.GroupBy == GroupByStatus:
+		value = object.Status
+	case strings.HasPrefix(p.GroupBy, GroupByLabelPrefix):
+		value = object.Labels[strings.TrimPrefix(p.GroupBy, GroupByLabelPrefix)]
+	}
+	if len(value) == 0 {
+		return noValue
+	}
+	return value
+}
+
+// groupsOf returns the number of objects per group, from the largest group
+func groupsOf(objects []Object, groupOf func(Object) string) []Group {
+	counts := make(map[string]int)
+	for _, object := range objects {
+		counts[groupOf(object)]++
+	}
+
+	groups := []Group{}
+	for label, value := range counts {
+		groups = append(groups, Group{Label: label, Value: value})
+	}
+	sort.Sort(groupsBySize(groups))
+	return groups
+}
+
+// series returns the number of objects created per day during the days of the panel (per group, if any),
+// and the keys of the series
+func (p PanelDefinition) series(objects []Object, now time.Time) ([]map[string]interface{}, []string) {
+	days := p.Days
+	if days == 0 {
+		days = defaultDays
+	}
+
+	keys := []string{"count"}
+	if len(p.GroupBy) > 0 {
+		keys = []string{}
+		for _, group := range groupsOf(objects, p.groupOf) {
+			keys = append(keys, group.Label)
+		}
+	}
+
+	points := make(map[string]map[string]interface{})
+	series := []map[string]interface{}{}
+	for i := days - 1; i >= 0; i-- {
+		date := now.AddDate(0, 0, -i).Format("2006-01-02")
+		point := map[string]interface{}{"date": date}
+		for _, key := range keys {
+			point[key] = 0
+		}
+		points[date] = point
+		series = append(series, point)
+	}
+
+	for _, object := range objects {
+		point, found := points[object.Created.Format("2006-01-02")]
+		if !found {
+			continue
+		}
+		key := "count"
+		if len(p.GroupBy) > 0 {
+			key = p.groupOf(object)
+		}
+		point[key] = point[key].(int) + 1
+	}
+	return series, keys
+}
+
+// defaultTitle returns the title of a panel without title, such as "pods by status"
+func (p PanelDefinition) defaultTitle() string {
+	title := "Active alerts"
+	if p.Type != PanelTypeAlerts {
+		title = strings.ToUpper(string(p.Resource[:1])) + string(p.Resource[1:])
+		if p.Resource != api.ResourceTypeEndpoints {
+			title += "s"
+		}
+	}
+	if len(p.GroupBy) > 0 && p.Type != PanelTypeCounter && p.Type != PanelTypeAlerts {
+		title += " by " + strings.TrimPrefix(p.GroupBy, GroupByLabelPrefix)
+	}
+	return title
+}
+
+// ColumnWidth returns the width of the panel, in bootstrap columns
+func (p PanelDefinition) ColumnWidth() int {
+	if p.Width > 0 {
+		return p.Width
+	}
+	switch p.Type {
+	case PanelTypeCounter:
+		return 3
+	case PanelTypeDonut:
+		return 4
+	default:
+		return 12
+	}
+}
+
+// objectsByCreation sorts the objects from the most recent one
+type objectsByCreation []Object
+
+func (o objectsByCreation) Len() int           { return len(o) }
+func (o objectsByCreation) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o objectsByCreation) Less(i, j int) bool { return o[i].Created.After(o[j].Created) }
+
+// groupsBySize sorts the groups from the largest one, then by label
+type groupsBySize []Group
+
+func (g groupsBySize) Len() int      { return len(g) }
+func (g groupsBySize) Swap(i, j int) { g[i], g[j] = g[j], g[i] }
+func (g groupsBySize) Less(i, j int) bool {
+	if g[i].Value != g[j].Value {
+		return g[i].Value > g[j].Value
+	}
+	return g[i].Label < g[j].Label
+}
@@ -0,0 +1,37 @@
+// Package duration provides the durations of the configuration and of the alerting rules,
+// read from (and written to) JSON and YAML as strings such as "10m"
+package duration
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that can be read from a string such as "10m" or "1h30m"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("Invalid duration %s: it should be a string such as \"10m\"", data)
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// String returns the duration as a string such as "10m0s"
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
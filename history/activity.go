@@ -0,0 +1,143 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// RecentActivityPeriod is the period of the daily activity kept in memory (for the home page),
+	// so that it is not read from the files on each request
+	RecentActivityPeriod = 90 * 24 * time.Hour
+
+	// activityMargin is how long before the start of the daily activity the transitions are read,
+	// for the clock skew between the API and the dashboard
+	activityMargin = 24 * time.Hour
+)
+
+// activity accumulates the daily activity of each namespace, from the transitions
+type activity struct {
+	// since is the start of the activity: the objects created (or the statuses reached) before are ignored
+	since time.Time
+
+	// days is the activity of each day, per namespace and per date
+	days map[string]map[string]*DailyActivity
+
+	// seen is when each counted creation or status happened, because the same transition
+	// may have been recorded twice, if the state has been lost
+	seen map[string]time.Time
+}
+
+// newActivity builds a new activity instance, that starts at the given time
+func newActivity(since time.Time) *activity {
+	return &activity{
+		since: since,
+		days:  make(map[string]map[string]*DailyActivity),
+		seen:  make(map[string]time.Time),
+	}
+}
+
+// add counts the creation of the object of the given transition, if it is seen for the first time,
+// and the status it reached
+func (a *activity) add(transition Transition) {
+	dayOf := func(t time.Time) *DailyActivity {
+		if a.days[transition.Namespace] == nil {
+			a.days[transition.Namespace] = make(map[string]*DailyActivity)
+		}
+		date := t.Local().Format(dayFormat)
+		if a.days[transition.Namespace][date] == nil {
+			a.days[transition.Namespace][date] = &DailyActivity{
+				Date:               date,
+				BuildStatuses:      make(map[string]int),
+				DeploymentStatuses: make(map[string]int),
+			}
+		}
+		return a.days[transition.Namespace][date]
+	}
+
+	key := transition.key()
+	if _, seen := a.seen[key]; len(transition.From) == 0 && !seen && !transition.Created.Before(a.since) {
+		a.seen[key] = transition.Created
+		switch transition.Kind {
+		case KindBuild:
+			dayOf(transition.Created).Builds++
+		case KindDeployment:
+			dayOf(transition.Created).Deployments++
+		}
+	}
+
+	// the status of an object seen for the first time may have been reached long before
+	reachedAt := transition.Time
+	if len(transition.From) == 0 {
+		reachedAt = transition.Created
+	}
+	key = key + "|" + transition.To
+	if _, seen := a.seen[key]; seen || reachedAt.Before(a.since) {
+		return
+	}
+	a.seen[key] = reachedAt
+	switch transition.Kind {
+	case KindBuild:
+		dayOf(reachedAt).BuildStatuses[transition.To]++
+	case KindDeployment:
+		dayOf(reachedAt).DeploymentStatuses[transition.To]++
+	}
+}
+
+// forget moves the start of the activity to the given time, and forgets the days before
+func (a *activity) forget(since time.Time) {
+	a.since = since
+	first := since.Local().Format(dayFormat)
+	for namespace, days := range a.days {
+		for date := range days {
+			if date < first {
+				delete(days, date)
+			}
+		}
+		if len(days) == 0 {
+			delete(a.days, namespace)
+		}
+	}
+	for key, t := range a.seen {
+		if t.Before(since) {
+			delete(a.seen, key)
+		}
+	}
+}
+
+// daily returns the activity of each day, the oldest first,
+// for the given namespaces (or for all namespaces if nil)
+func (a *activity) daily(namespaces map[string]bool) []DailyActivity {
+	days := make(map[string]*DailyActivity)
+	for namespace, namespaceDays := range a.days {
+		if namespaces != nil && !namespaces[namespace] {
+			continue
+		}
+		for date, day := range namespaceDays {
+			total := days[date]
+			if total == nil {
+				total = &DailyActivity{
+					Date:               date,
+					BuildStatuses:      make(map[string]int),
+					DeploymentStatuses: make(map[string]int),
+				}
+				days[date] = total
+			}
+			total.Builds += day.Builds
+			total.Deployments += day.Deployments
+			for status, count := range day.BuildStatuses {
+				total.BuildStatuses[status] += count
+			}
+			for status, count := range day.DeploymentStatuses {
+				total.DeploymentStatuses[status] += count
+			}
+		}
+	}
+
+	results := []DailyActivity{}
+	for _, day := range days {
+		results = append(results, *day)
+	}
+	sort.Sort(activityByDate(results))
+	return results
+}
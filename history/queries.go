@@ -0,0 +1,147 @@
+package history
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DailyActivity is the number of builds and deployments created during a day,
+// and the number of builds and deployments that reached each status during this day
+type DailyActivity struct {
+	Date               string         `json:"date"`
+	Builds             int            `json:"builds"`
+	Deployments        int            `json:"deployments"`
+	BuildStatuses      map[string]int `json:"buildStatuses"`
+	DeploymentStatuses map[string]int `json:"deploymentStatuses"`
+}
+
+// TrendPoint is the average number of objects during a day
+type TrendPoint struct {
+	Date   string `json:"date"`
+	Counts Counts `json:"counts"`
+}
+
+// Samples returns the samples recorded since the given time, the oldest first
+func (s *Store) Samples(since time.Time) ([]Sample, error) {
+	samples := []Sample{}
+	err := s.readFiles(samplesPrefix, since, func(decoder *json.Decoder) error {
+		sample := Sample{}
+		if err := decoder.Decode(&sample); err != nil {
+			return err
+		}
+		if !sample.Time.Before(since) {
+			samples = append(samples, sample)
+		}
+		return nil
+	})
+	sort.Sort(samplesByTime(samples))
+	return samples, err
+}
+
+// Transitions returns the transitions recorded since the given time, the oldest first
+func (s *Store) Transitions(since time.Time) ([]Transition, error) {
+	transitions := []Transition{}
+	err := s.readFiles(transitionsPrefix, since, func(decoder *json.Decoder) error {
+		transition := Transition{}
+		if err := decoder.Decode(&transition); err != nil {
+			return err
+		}
+		if !transition.Time.Before(since) {
+			transitions = append(transitions, transition)
+		}
+		return nil
+	})
+	return transitions, err
+}
+
+// DailyActivity returns the activity of each day since the given time, the oldest first,
+// for the given namespaces (or for all namespaces if nil).
+// It reads the transitions from the files: RecentActivity is faster, for the recent activity.
+func (s *Store) DailyActivity(since time.Time, namespaces map[string]bool) ([]DailyActivity, error) {
+	// a transition is recorded after the object has been created (or has reached its status),
+	// so the transitions recorded before the given time are not needed (but for a margin, for the clock skew with the API)
+	transitions, err := s.Transitions(since.Add(-activityMargin))
+	if err != nil {
+		return nil, err
+	}
+
+	activity := newActivity(since)
+	for _, transition := range transitions {
+		activity.add(transition)
+	}
+	return activity.daily(namespaces), nil
+}
+
+// RecentActivity returns the activity of each day of the RecentActivityPeriod, the oldest first,
+// for the given namespaces (or for all namespaces if nil). It is kept in memory, and updated by Record.
+func (s *Store) RecentActivity(namespaces map[string]bool) []DailyActivity {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.recent.daily(namespaces)
+}
+
+// DailyTrend returns the average number of objects of each day since the given time, the oldest first,
+// for the given namespaces (or for all namespaces if nil)
+func (s *Store) DailyTrend(since time.Time, namespaces map[string]bool) ([]TrendPoint, error) {
+	samples, err := s.Samples(since)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []string{}
+	totals := make(map[string][]Sample)
+	for _, sample := range samples {
+		date := sample.Time.Local().Format(dayFormat)
+		if _, found := totals[date]; !found {
+			days = append(days, date)
+		}
+		totals[date] = append(totals[date], Sample{
+			Time:   sample.Time,
+			Counts: map[string]Counts{"": sample.Total(namespaces)},
+		})
+	}
+
+	trend := []TrendPoint{}
+	for _, date := range days {
+		average := averageOf(time.Time{}, totals[date])
+		trend = append(trend, TrendPoint{
+			Date:   date,
+			Counts: average.Counts[""],
+		})
+	}
+	return trend, nil
+}
+
+// readFiles reads the records of the files with the given prefix, for the days since the given time
+func (s *Store) readFiles(prefix string, since time.Time, read func(decoder *json.Decoder) error) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	firstDay := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.Local)
+	for _, file := range files {
+		filePrefix, day, _, ok := parseFileName(file.Name())
+		if !ok || filePrefix != prefix || day.Before(firstDay) {
+			continue
+		}
+		if err := readRecords(filepath.Join(s.dir, file.Name()), read); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activityByDate sorts the daily activities, the oldest first
+type activityByDate []DailyActivity
+
+func (a activityByDate) Len() int           { return len(a) }
+func (a activityByDate) Less(i, j int) bool { return a[i].Date < a[j].Date }
+func (a activityByDate) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
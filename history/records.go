@@ -0,0 +1,149 @@
+package history
+
+import (
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+)
+
+// Counts are the number of objects per resource type (such as "pod"),
+// and per resource type and status (such as "build/Failed")
+type Counts map[string]int
+
+// Sample is a snapshot of the number of objects, per namespace
+type Sample struct {
+	Time   time.Time         `json:"time"`
+	Counts map[string]Counts `json:"counts"`
+}
+
+// Total returns the sum of the counts of the given namespaces (or of all namespaces if nil)
+func (s *Sample) Total(namespaces map[string]bool) Counts {
+	total := Counts{}
+	for namespace, counts := range s.Counts {
+		if namespaces != nil && !namespaces[namespace] {
+			continue
+		}
+		for key, count := range counts {
+			total[key] += count
+		}
+	}
+	return total
+}
+
+// Transition is a change of the status of an object (a build or a deployment).
+// The first time an object is seen, its transition has no From status.
+type Transition struct {
+	Time        time.Time `json:"time"`
+	Kind        string    `json:"kind"`
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	Application string    `json:"application,omitempty"`
+	Created     time.Time `json:"created"`
+	From        string    `json:"from,omitempty"`
+	To          string    `json:"to"`
+}
+
+// key identifies the object of the transition
+func (t *Transition) key() string {
+	return t.Kind + "/" + t.Namespace + "/" + t.Name
+}
+
+const (
+	// KindBuild is the kind of the transitions of the builds
+	KindBuild = "Build"
+
+	// KindDeployment is the kind of the transitions of the deployments (ReplicationControllers)
+	KindDeployment = "Deployment"
+)
+
+// newSample builds a sample with the counts of the given data
+func newSample(data *api.Data, now time.Time) *Sample {
+	sample := &Sample{
+		Time:   now,
+		Counts: make(map[string]Counts),
+	}
+	add := func(namespace string, key string, n int) {
+		counts, found := sample.Counts[namespace]
+		if !found {
+			counts = Counts{}
+			sample.Counts[namespace] = counts
+		}
+		counts[key] += n
+	}
+	count := func(namespace string, key string) {
+		add(namespace, key, 1)
+	}
+
+	for _, route := range data.Routes {
+		count(route.Namespace, string(api.ResourceTypeRoute))
+	}
+	for _, service := range data.Services {
+		count(service.Namespace, string(api.ResourceTypeService))
+	}
+	for _, pod := range data.Pods {
+		count(pod.Namespace, string(api.ResourceTypePod))
+		count(pod.Namespace, string(api.ResourceTypePod)+"/"+string(pod.Status.Phase))
+		add(pod.Namespace, string(api.ResourceTypeContainer), len(pod.Spec.Containers))
+	}
+	for _, is := range data.ImageStreams {
+		count(is.Namespace, string(api.ResourceTypeImageStream))
+	}
+	for _, bc := range data.BuildConfigs {
+		count(bc.Namespace, string(api.ResourceTypeBuildConfig))
+	}
+	for _, build := range data.Builds {
+		count(build.Namespace, string(api.ResourceTypeBuild))
+		count(build.Namespace, string(api.ResourceTypeBuild)+"/"+string(build.Status.Phase))
+	}
+	for _, dc := range data.DeploymentConfigs {
+		count(dc.Namespace, string(api.ResourceTypeDeploymentConfig))
+	}
+	for _, rc := range data.ReplicationControllers {
+		count(rc.Namespace, string(api.ResourceTypeReplicationController))
+		if len(api.DeploymentConfigNameOf(rc)) > 0 {
+			count(rc.Namespace, "deployment/"+string(api.DeploymentStatusOf(rc)))
+		}
+	}
+	return sample
+}
+
+// currentStatuses returns the transitions that describe the current status of the builds and deployments
+// of the given data (without the From status)
+func currentStatuses(data *api.Data, now time.Time) []Transition {
+	buildConfigApps := make(map[string]string)
+	for _, bc := range data.BuildConfigs {
+		buildConfigApps[bc.Namespace+"/"+bc.Name] = data.ApplicationOf(bc.Labels)
+	}
+
+	transitions := []Transition{}
+	for _, build := range data.Builds {
+		application := data.ApplicationOf(build.Labels)
+		if len(application) == 0 {
+			application = buildConfigApps[build.Namespace+"/"+api.BuildConfigNameOf(build)]
+		}
+		transitions = append(transitions, Transition{
+			Time:        now,
+			Kind:        KindBuild,
+			Namespace:   build.Namespace,
+			Name:        build.Name,
+			Application: application,
+			Created:     build.CreationTimestamp.Time,
+			To:          string(build.Status.Phase),
+		})
+	}
+	for _, rc := range data.ReplicationControllers {
+		if len(api.DeploymentConfigNameOf(rc)) == 0 {
+			continue
+		}
+		transitions = append(transitions, Transition{
+			Time:        now,
+			Kind:        KindDeployment,
+			Namespace:   rc.Namespace,
+			Name:        rc.Name,
+			Application: data.ApplicationOf(rc.Labels),
+			Created:     rc.CreationTimestamp.Time,
+			To:          string(api.DeploymentStatusOf(rc)),
+		})
+	}
+	return transitions
+}
@@ -0,0 +1,334 @@
+// Package history provides an on-disk store of the history of the resources,
+// to display trends beyond the retention of the API.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+)
+
+const (
+	// dayFormat is the format of the dates in the names of the files
+	dayFormat = "2006-01-02"
+
+	// samplesPrefix and transitionsPrefix are the prefixes of the names of the files
+	samplesPrefix     = "samples-"
+	transitionsPrefix = "transitions-"
+
+	// hourlySuffix is the suffix of the samples files that have been downsampled
+	hourlySuffix = ".hourly"
+
+	// fileExtension is the extension of the files: one JSON record per line
+	fileExtension = ".jsonl"
+
+	// stateFile is the name of the file that stores the last known status of the objects
+	stateFile = "state.json"
+
+	// maintenanceInterval is the interval between two maintenances (retention and downsampling)
+	maintenanceInterval = 1 * time.Hour
+)
+
+// Store records the history of the resources on disk, in a directory (which should be on a persistent volume).
+// There is a file per day for the samples (the number of objects), and a file per day for the transitions
+// (the changes of the status of the builds and deployments).
+// The files older than the retention are removed, and the samples older than the downsampling delay
+// are downsampled to a sample per hour.
+type Store struct {
+	dir             string
+	retention       time.Duration
+	downsampleAfter time.Duration
+
+	mutex           sync.RWMutex
+	statuses        map[string]string
+	lastMaintenance time.Time
+
+	// recent is the activity of the RecentActivityPeriod, per namespace
+	recent *activity
+}
+
+// NewStore builds a new Store instance, that stores its files in the given directory
+func NewStore(dir string, retention time.Duration, downsampleAfter time.Duration) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	store := &Store{
+		dir:             dir,
+		retention:       retention,
+		downsampleAfter: downsampleAfter,
+		statuses:        make(map[string]string),
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, stateFile))
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(content, &store.statuses); err != nil {
+			return nil, fmt.Errorf("Failed to read the history state file: %v", err)
+		}
+	}
+
+	// the recent activity is read once from the files, and then updated with the new transitions
+	since := time.Now().Add(-RecentActivityPeriod)
+	transitions, err := store.Transitions(since.Add(-activityMargin))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the history transitions: %v", err)
+	}
+	store.recent = newActivity(since)
+	for _, transition := range transitions {
+		store.recent.add(transition)
+	}
+
+	return store, nil
+}
+
+// Retention returns how long the history is kept
+func (s *Store) Retention() time.Duration {
+	return s.retention
+}
+
+// Record records a sample and the transitions of the given data.
+// It can be registered as an api.RefreshListener.
+func (s *Store) Record(data *api.Data, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.appendRecord(samplesPrefix, now, newSample(data, now)); err != nil {
+		log.Printf("Failed to record the history sample: %v", err)
+	}
+
+	statuses := make(map[string]string)
+	for _, transition := range currentStatuses(data, now) {
+		key := transition.key()
+		statuses[key] = transition.To
+
+		previous, known := s.statuses[key]
+		if known && previous == transition.To {
+			continue
+		}
+		transition.From = previous
+		if err := s.appendRecord(transitionsPrefix, now, transition); err != nil {
+			log.Printf("Failed to record the history transition: %v", err)
+		}
+		s.recent.add(transition)
+	}
+	s.statuses = statuses
+
+	if err := s.writeState(); err != nil {
+		log.Printf("Failed to write the history state: %v", err)
+	}
+
+	if now.Sub(s.lastMaintenance) > maintenanceInterval {
+		s.maintain(now)
+		s.recent.forget(now.Add(-RecentActivityPeriod))
+		s.lastMaintenance = now
+	}
+}
+
+// appendRecord appends the given record to the file of the given day
+func (s *Store) appendRecord(prefix string, day time.Time, record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, prefix+day.Format(dayFormat)+fileExtension)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// writeState writes the last known status of the objects, so that the transitions
+// are not recorded again after a restart
+func (s *Store) writeState() error {
+	content, err := json.Marshal(s.statuses)
+	if err != nil {
+		return err
+	}
+
+	// write then rename, to never leave a partial file
+	path := filepath.Join(s.dir, stateFile)
+	if err := ioutil.WriteFile(path+".tmp", content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// maintain removes the files older than the retention,
+// and downsamples the samples older than the downsampling delay
+func (s *Store) maintain(now time.Time) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		log.Printf("Failed to list the history files: %v", err)
+		return
+	}
+
+	for _, file := range files {
+		prefix, day, hourly, ok := parseFileName(file.Name())
+		if !ok {
+			continue
+		}
+		path := filepath.Join(s.dir, file.Name())
+
+		// the files are per day, so compare with the end of the day
+		age := now.Sub(day.AddDate(0, 0, 1))
+		switch {
+		case age > s.retention:
+			if err := os.Remove(path); err != nil {
+				log.Printf("Failed to remove the history file %v: %v", path, err)
+			}
+		case prefix == samplesPrefix && !hourly && age > s.downsampleAfter:
+			if err := s.downsample(path, day); err != nil {
+				log.Printf("Failed to downsample the history file %v: %v", path, err)
+			}
+		}
+	}
+}
+
+// downsample replaces the samples of the given file by a sample per hour
+// (with the average counts of the hour)
+func (s *Store) downsample(path string, day time.Time) error {
+	samples := []Sample{}
+	err := readRecords(path, func(decoder *json.Decoder) error {
+		sample := Sample{}
+		if err := decoder.Decode(&sample); err != nil {
+			return err
+		}
+		samples = append(samples, sample)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	hourlyPath := filepath.Join(s.dir, samplesPrefix+day.Format(dayFormat)+hourlySuffix+fileExtension)
+	file, err := os.Create(hourlyPath + ".tmp")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	for _, sample := range averagePerHour(samples) {
+		if err := encoder.Encode(sample); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(hourlyPath+".tmp", hourlyPath); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// averagePerHour returns a sample per hour, with the average counts of the samples of the hour
+func averagePerHour(samples []Sample) []Sample {
+	hours := make(map[int64][]Sample)
+	for _, sample := range samples {
+		hour := sample.Time.Truncate(time.Hour).Unix()
+		hours[hour] = append(hours[hour], sample)
+	}
+
+	results := []Sample{}
+	for hour, hourSamples := range hours {
+		results = append(results, averageOf(time.Unix(hour, 0), hourSamples))
+	}
+	sort.Sort(samplesByTime(results))
+	return results
+}
+
+// averageOf returns a sample with the average counts of the given samples
+func averageOf(t time.Time, samples []Sample) Sample {
+	sums := make(map[string]Counts)
+	for _, sample := range samples {
+		for namespace, counts := range sample.Counts {
+			if sums[namespace] == nil {
+				sums[namespace] = Counts{}
+			}
+			for key, count := range counts {
+				sums[namespace][key] += count
+			}
+		}
+	}
+
+	average := Sample{Time: t, Counts: make(map[string]Counts)}
+	for namespace, counts := range sums {
+		average.Counts[namespace] = Counts{}
+		for key, sum := range counts {
+			// rounded to the nearest integer
+			average.Counts[namespace][key] = (sum*2 + len(samples)) / (len(samples) * 2)
+		}
+	}
+	return average
+}
+
+// parseFileName extracts the prefix and the day from the name of a history file
+func parseFileName(name string) (prefix string, day time.Time, hourly bool, ok bool) {
+	if !strings.HasSuffix(name, fileExtension) {
+		return "", time.Time{}, false, false
+	}
+	name = strings.TrimSuffix(name, fileExtension)
+
+	if strings.HasSuffix(name, hourlySuffix) {
+		hourly = true
+		name = strings.TrimSuffix(name, hourlySuffix)
+	}
+
+	for _, p := range []string{samplesPrefix, transitionsPrefix} {
+		if strings.HasPrefix(name, p) {
+			d, err := time.ParseInLocation(dayFormat, strings.TrimPrefix(name, p), time.Local)
+			if err != nil {
+				return "", time.Time{}, false, false
+			}
+			return p, d, hourly, true
+		}
+	}
+	return "", time.Time{}, false, false
+}
+
+// readRecords calls the given function for each record of the given file, until the end of the file
+func readRecords(path string, read func(decoder *json.Decoder) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	for {
+		if err := read(decoder); err == io.EOF {
+			return nil
+		} else if err != nil {
+			// most likely a partial record, written while the dashboard was stopped
+			log.Printf("Ignoring the end of the history file %v: %v", path, err)
+			return nil
+		}
+	}
+}
+
+// samplesByTime sorts the samples, the oldest first
+type samplesByTime []Sample
+
+func (s samplesByTime) Len() int           { return len(s) }
+func (s samplesByTime) Less(i, j int) bool { return s[i].Time.Before(s[j].Time) }
+func (s samplesByTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -1,7 +1,13 @@
 package main
 
-import "github.com/vbehar/openshift-dashboard/web"
+import (
+	"os"
+
+	"github.com/vbehar/openshift-dashboard/cmd"
+)
 
 func main() {
-	web.RunHttpServer()
+	if err := cmd.NewRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,222 @@
+package notify
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+const (
+	// topWarningEvents is the number of warning events displayed per application in the digest
+	topWarningEvents = 5
+)
+
+// Digest is a summary of what happened to the applications during a period
+type Digest struct {
+	Title        string
+	DashboardURL string
+	Since        time.Time
+	Until        time.Time
+	Applications []ApplicationDigest
+}
+
+// ApplicationDigest is a summary of what happened to an application in a namespace during a period
+type ApplicationDigest struct {
+	Name          string
+	Namespace     string
+	Health        api.Health
+	BuildsRun     int
+	FailedBuilds  []buildapi.Build
+	Deployments   []kapi.ReplicationController
+	CrashingPods  []CrashingPod
+	WarningEvents []kapi.Event
+}
+
+// CrashingPod is a pod whose containers terminated during the period
+type CrashingPod struct {
+	Namespace string
+	Name      string
+	Restarts  int
+	Reason    string
+}
+
+// NewApplicationDigest builds the summary of the given application in the given namespace, since the given time
+func NewApplicationDigest(data *api.Data, application string, namespace string, since time.Time) ApplicationDigest {
+	namespaceData := data.ForNamespace(namespace)
+	appData := namespaceData.ForApplication(application)
+	digest := ApplicationDigest{
+		Name:      application,
+		Namespace: namespace,
+		Health:    data.ApplicationHealthOf(application, namespace),
+	}
+
+	for _, build := range appData.Builds {
+		if build.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+		digest.BuildsRun++
+		if build.Status.Phase == buildapi.BuildPhaseFailed || build.Status.Phase == buildapi.BuildPhaseError {
+			digest.FailedBuilds = append(digest.FailedBuilds, build)
+		}
+	}
+
+	for _, rc := range appData.ReplicationControllers {
+		if !rc.CreationTimestamp.Time.Before(since) {
+			digest.Deployments = append(digest.Deployments, rc)
+		}
+	}
+
+	// the objects of the application, to find their events
+	objects := make(map[string]bool)
+	for _, pod := range appData.Pods {
+		objects["Pod/"+pod.Namespace+"/"+pod.Name] = true
+		if crashingPod, crashing := crashingPodOf(pod, since); crashing {
+			digest.CrashingPods = append(digest.CrashingPods, crashingPod)
+		}
+	}
+	for _, rc := range appData.ReplicationControllers {
+		objects["ReplicationController/"+rc.Namespace+"/"+rc.Name] = true
+	}
+	for _, dc := range appData.DeploymentConfigs {
+		objects["DeploymentConfig/"+dc.Namespace+"/"+dc.Name] = true
+	}
+	for _, build := range appData.Builds {
+		objects["Build/"+build.Namespace+"/"+build.Name] = true
+	}
+
+	for _, event := range namespaceData.Events {
+		if !api.IsWarningEvent(event) || event.LastTimestamp.Time.Before(since) {
+			continue
+		}
+		involved := event.InvolvedObject
+		if objects[involved.Kind+"/"+involved.Namespace+"/"+involved.Name] {
+			digest.WarningEvents = append(digest.WarningEvents, event)
+		}
+	}
+	sort.Sort(eventsByCount(digest.WarningEvents))
+	if len(digest.WarningEvents) > topWarningEvents {
+		digest.WarningEvents = digest.WarningEvents[:topWarningEvents]
+	}
+
+	return digest
+}
+
+// crashingPodOf returns the given pod as a CrashingPod if one of its containers
+// is waiting to be restarted, or terminated since the given time
+func crashingPodOf(pod kapi.Pod, since time.Time) (CrashingPod, bool) {
+	crashingPod := CrashingPod{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Restarts:  api.RestartCountOf(pod),
+	}
+
+	crashing := false
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff" {
+			crashing = true
+			crashingPod.Reason = status.State.Waiting.Reason
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && !terminated.FinishedAt.Time.Before(since) {
+			crashing = true
+			if len(crashingPod.Reason) == 0 {
+				crashingPod.Reason = fmt.Sprintf("%v (exit code %d)", terminated.Reason, terminated.ExitCode)
+			}
+		}
+	}
+	return crashingPod, crashing
+}
+
+// eventsByCount sorts the events, the most frequent first
+type eventsByCount []kapi.Event
+
+func (e eventsByCount) Len() int           { return len(e) }
+func (e eventsByCount) Less(i, j int) bool { return e[i].Count > e[j].Count }
+func (e eventsByCount) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+
+// SendDigests sends the digests of the period ending now to all the recipients.
+// Each recipient receives a single digest, with the applications they have subscribed to, per namespace
+// (all of them for the default recipients): the recipients of a namespace never receive the digest
+// of an application in another namespace, even if it has the same name.
+func (m *Mailer) SendDigests(data *api.Data, since time.Time, until time.Time) error {
+	digests := make(map[string]*Digest)
+	for _, app := range data.Applications {
+		for _, namespace := range namespacesOf(data.ForApplication(app.Name())) {
+			appDigest := NewApplicationDigest(data, app.Name(), namespace, since)
+			for _, recipient := range m.RecipientsFor(data, app.Name(), namespace) {
+				digest, found := digests[recipient]
+				if !found {
+					digest = &Digest{Since: since, Until: until}
+					digests[recipient] = digest
+				}
+				digest.Applications = append(digest.Applications, appDigest)
+			}
+		}
+	}
+
+	var lastErr error
+	for recipient, digest := range digests {
+		if err := m.SendDigest(digest, recipient); err != nil {
+			log.Printf("Failed to send the digest to %v: %v", recipient, err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// namespacesOf returns the sorted namespaces of the BuildConfigs, builds, DeploymentConfigs,
+// ReplicationControllers and pods of the given data
+func namespacesOf(data *api.Data) []string {
+	set := make(map[string]bool)
+	for _, bc := range data.BuildConfigs {
+		set[bc.Namespace] = true
+	}
+	for _, build := range data.Builds {
+		set[build.Namespace] = true
+	}
+	for _, dc := range data.DeploymentConfigs {
+		set[dc.Namespace] = true
+	}
+	for _, rc := range data.ReplicationControllers {
+		set[rc.Namespace] = true
+	}
+	for _, pod := range data.Pods {
+		set[pod.Namespace] = true
+	}
+
+	namespaces := []string{}
+	for namespace := range set {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// RunDigests sends the digests every day at the given time (hour and minute, in the local timezone),
+// with the latest data of the given refresher.
+// It blocks, so it should be run in its own goroutine.
+func (m *Mailer) RunDigests(refresher *api.Refresher, hour int, minute int) {
+	for {
+		now := time.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		time.Sleep(next.Sub(now))
+
+		data, _ := refresher.Data()
+		if data == nil {
+			log.Printf("Can't send the digests: no data available yet")
+			continue
+		}
+		if err := m.SendDigests(data, next.AddDate(0, 0, -1), next); err == nil {
+			log.Printf("Sent the daily digests")
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Package notify sends notifications (to webhooks, ...) when the state of the resources changes
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+)
+
+// EventType describes the possible types of events
+type EventType string
+
+const (
+	EventTypeBuildFailed             EventType = "build-failed"
+	EventTypeBuildRecovered          EventType = "build-recovered"
+	EventTypeDeploymentFailed        EventType = "deployment-failed"
+	EventTypeDeploymentCompleted     EventType = "deployment-completed"
+	EventTypeApplicationHealthChange EventType = "application-health-changed"
+)
+
+// Event is a state transition, detected between two refreshes of the data
+type Event struct {
+	Type           EventType `json:"type"`
+	Application    string    `json:"application,omitempty"`
+	Namespace      string    `json:"namespace,omitempty"`
+	Kind           string    `json:"kind"`
+	Name           string    `json:"name"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previousStatus,omitempty"`
+	Message        string    `json:"message"`
+	Time           time.Time `json:"time"`
+}
+
+// Key identifies the event, to deduplicate the notifications
+func (e *Event) Key() string {
+	return string(e.Type) + "|" + e.Namespace + "|" + e.Kind + "|" + e.Name + "|" + e.Status
+}
+
+// IsBad returns true if the event is about something that went wrong
+func (e *Event) IsBad() bool {
+	switch e.Type {
+	case EventTypeBuildFailed, EventTypeDeploymentFailed:
+		return true
+	case EventTypeApplicationHealthChange:
+		return e.Status != string(api.HealthHealthy)
+	}
+	return false
+}
+
+// IsCritical returns true if the event needs immediate attention:
+// a failed build or deployment, or an application that is down
+func (e *Event) IsCritical() bool {
+	switch e.Type {
+	case EventTypeBuildFailed, EventTypeDeploymentFailed:
+		return true
+	case EventTypeApplicationHealthChange:
+		return e.Status == string(api.HealthDown)
+	}
+	return false
+}
+
+// DetectEvents returns the state transitions between the previous and the current data
+func DetectEvents(previous *api.Data, current *api.Data, now time.Time) []Event {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	events := []Event{}
+	events = append(events, detectBuildEvents(previous, current, now)...)
+	events = append(events, detectDeploymentEvents(previous, current, now)...)
+	events = append(events, detectApplicationEvents(previous, current, now)...)
+	return events
+}
+
+// detectBuildEvents returns the builds that failed, and the builds that succeeded after a failure
+func detectBuildEvents(previous *api.Data, current *api.Data, now time.Time) []Event {
+	previousPhases := make(map[string]buildapi.BuildPhase, len(previous.Builds))
+	for _, build := range previous.Builds {
+		previousPhases[build.Namespace+"/"+build.Name] = build.Status.Phase
+	}
+
+	events := []Event{}
+	for _, bc := range current.BuildConfigs {
+		builds := current.BuildsOf(bc)
+		for i, build := range builds {
+			previousPhase := previousPhases[build.Namespace+"/"+build.Name]
+			if previousPhase == build.Status.Phase {
+				continue
+			}
+
+			event := Event{
+				Application:    current.ApplicationOf(bc.Labels),
+				Namespace:      build.Namespace,
+				Kind:           "Build",
+				Name:           build.Name,
+				Status:         string(build.Status.Phase),
+				PreviousStatus: string(previousPhase),
+				Time:           now,
+			}
+
+			switch build.Status.Phase {
+			case buildapi.BuildPhaseFailed, buildapi.BuildPhaseError:
+				event.Type = EventTypeBuildFailed
+				event.Message = fmt.Sprintf("Build %v of %v/%v failed", build.Name, bc.Namespace, bc.Name)
+			case buildapi.BuildPhaseComplete:
+				if !isFailedBuild(previousFinishedBuild(builds[i+1:])) {
+					continue
+				}
+				event.Type = EventTypeBuildRecovered
+				event.Message = fmt.Sprintf("Build %v of %v/%v succeeded after a failure", build.Name, bc.Namespace, bc.Name)
+			default:
+				continue
+			}
+
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// previousFinishedBuild returns the most recent build (from the given ones, the most recent first)
+// that completed or failed, ignoring the cancelled ones
+func previousFinishedBuild(builds []buildapi.Build) *buildapi.Build {
+	for i := range builds {
+		switch builds[i].Status.Phase {
+		case buildapi.BuildPhaseComplete, buildapi.BuildPhaseFailed, buildapi.BuildPhaseError:
+			return &builds[i]
+		}
+	}
+	return nil
+}
+
+// isFailedBuild returns true if the given build failed
+func isFailedBuild(build *buildapi.Build) bool {
+	if build == nil {
+		return false
+	}
+	return build.Status.Phase == buildapi.BuildPhaseFailed || build.Status.Phase == buildapi.BuildPhaseError
+}
+
+// detectDeploymentEvents returns the deployments that failed or completed
+func detectDeploymentEvents(previous *api.Data, current *api.Data, now time.Time) []Event {
+	previousStatuses := make(map[string]deployapi.DeploymentStatus, len(previous.ReplicationControllers))
+	for _, rc := range previous.ReplicationControllers {
+		previousStatuses[rc.Namespace+"/"+rc.Name] = api.DeploymentStatusOf(rc)
+	}
+
+	events := []Event{}
+	for _, rc := range current.ReplicationControllers {
+		dcName := api.DeploymentConfigNameOf(rc)
+		if len(dcName) == 0 {
+			continue
+		}
+
+		status := api.DeploymentStatusOf(rc)
+		previousStatus := previousStatuses[rc.Namespace+"/"+rc.Name]
+		if previousStatus == status {
+			continue
+		}
+
+		event := Event{
+			Application:    current.ApplicationOf(rc.Labels),
+			Namespace:      rc.Namespace,
+			Kind:           "Deployment",
+			Name:           rc.Name,
+			Status:         string(status),
+			PreviousStatus: string(previousStatus),
+			Time:           now,
+		}
+		if dc, found := current.FindDeploymentConfig(rc.Namespace, dcName); found && len(event.Application) == 0 {
+			event.Application = current.ApplicationOf(dc.Labels)
+		}
+
+		switch status {
+		case deployapi.DeploymentStatusFailed:
+			event.Type = EventTypeDeploymentFailed
+			event.Message = fmt.Sprintf("Deployment %v of %v/%v failed", rc.Name, rc.Namespace, dcName)
+		case deployapi.DeploymentStatusComplete:
+			event.Type = EventTypeDeploymentCompleted
+			event.Message = fmt.Sprintf("Deployment %v of %v/%v completed", rc.Name, rc.Namespace, dcName)
+		default:
+			continue
+		}
+
+		events = append(events, event)
+	}
+	return events
+}
+
+// detectApplicationEvents returns the applications whose health changed in a namespace
+func detectApplicationEvents(previous *api.Data, current *api.Data, now time.Time) []Event {
+	events := []Event{}
+	seen := make(map[string]bool)
+	for _, dc := range current.DeploymentConfigs {
+		application := current.ApplicationOf(dc.Labels)
+		if len(application) == 0 || seen[dc.Namespace+"/"+application] {
+			continue
+		}
+		seen[dc.Namespace+"/"+application] = true
+
+		health := current.ApplicationHealthOf(application, dc.Namespace)
+		previousHealth := previous.ApplicationHealthOf(application, dc.Namespace)
+		if health == previousHealth || previousHealth == api.HealthUnknown {
+			continue
+		}
+
+		events = append(events, Event{
+			Type:           EventTypeApplicationHealthChange,
+			Application:    application,
+			Namespace:      dc.Namespace,
+			Kind:           "Application",
+			Name:           application,
+			Status:         string(health),
+			PreviousStatus: string(previousHealth),
+			Message:        fmt.Sprintf("Application %v in %v is now %v (was %v)", application, dc.Namespace, health, previousHealth),
+			Time:           now,
+		})
+	}
+	return events
+}
@@ -0,0 +1,258 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"net"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+)
+
+const (
+	// RecipientsAnnotation is the annotation (on a project or on the DeploymentConfigs of an application)
+	// with the comma-separated emails of the people to notify
+	RecipientsAnnotation = "openshift-dashboard/email-recipients"
+)
+
+// TLSMode describes the possible ways to secure the connection to the SMTP server
+type TLSMode string
+
+const (
+	// TLSModeStartTLS upgrades the connection with STARTTLS, and fails if the server doesn't support it
+	TLSModeStartTLS TLSMode = "starttls"
+
+	// TLSModeTLS uses an implicit TLS connection (usually on port 465)
+	TLSModeTLS TLSMode = "tls"
+
+	// TLSModeNone uses a plain connection
+	TLSModeNone TLSMode = "none"
+)
+
+// MailerConfig is the configuration of the SMTP server and of the mails
+type MailerConfig struct {
+	Host     string
+	Port     string
+	TLS      TLSMode
+	Username string
+	Password string
+	From     string
+
+	// Recipients receive all the mails, in addition to the ones defined by the annotations
+	Recipients []string
+
+	// Title is used in the subject of the mails
+	Title string
+
+	// DashboardURL is the public URL of the dashboard, used for the links in the mails
+	DashboardURL string
+}
+
+// Mailer sends mails for the critical events, and the daily digests
+type Mailer struct {
+	config    MailerConfig
+	templates *template.Template
+}
+
+// NewMailer builds a new Mailer instance, with the mail templates read by the given function
+func NewMailer(config MailerConfig, readTemplate func(name string) ([]byte, error)) (*Mailer, error) {
+	if len(config.Host) == 0 {
+		return nil, fmt.Errorf("Missing SMTP host!")
+	}
+	if len(config.From) == 0 {
+		return nil, fmt.Errorf("Missing sender (from) address!")
+	}
+	switch config.TLS {
+	case "":
+		config.TLS = TLSModeStartTLS
+	case TLSModeStartTLS, TLSModeTLS, TLSModeNone:
+	default:
+		return nil, fmt.Errorf("Unknown SMTP TLS mode %v!", config.TLS)
+	}
+	if len(config.Port) == 0 {
+		config.Port = "25"
+		if config.TLS == TLSModeTLS {
+			config.Port = "465"
+		}
+	}
+
+	templates := template.New("mail")
+	for _, name := range []string{"digest.tmpl", "mail-event.tmpl"} {
+		content, err := readTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := templates.New(name).Parse(string(content)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Mailer{
+		config:    config,
+		templates: templates,
+	}, nil
+}
+
+// SendEvent sends a mail for the given event, to the given recipients
+func (m *Mailer) SendEvent(event Event, recipients []string) error {
+	var body bytes.Buffer
+	err := m.templates.ExecuteTemplate(&body, "mail-event.tmpl", map[string]interface{}{
+		"Event":        event,
+		"DashboardURL": m.config.DashboardURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("[%v] %v", m.config.Title, event.Message)
+	return m.send(recipients, subject, body.Bytes())
+}
+
+// SendDigest sends the given digest to the given recipient
+func (m *Mailer) SendDigest(digest *Digest, recipient string) error {
+	digest.Title = m.config.Title
+	digest.DashboardURL = m.config.DashboardURL
+
+	var body bytes.Buffer
+	if err := m.templates.ExecuteTemplate(&body, "digest.tmpl", digest); err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("[%v] Daily digest for %v", m.config.Title, digest.Until.Format("2006-01-02"))
+	return m.send([]string{recipient}, subject, body.Bytes())
+}
+
+// RecipientsFor returns the recipients of the mails about the given application in the given namespace:
+// the default recipients, and the ones defined by the annotations of the project
+// and of the DeploymentConfigs of the application
+func (m *Mailer) RecipientsFor(data *api.Data, application string, namespace string) []string {
+	recipients := make(map[string]bool)
+	for _, recipient := range m.config.Recipients {
+		recipients[recipient] = true
+	}
+
+	for _, project := range data.Projects {
+		if project.Name == namespace {
+			addRecipients(recipients, project.Annotations[RecipientsAnnotation])
+		}
+	}
+
+	if len(application) > 0 {
+		for _, dc := range data.DeploymentConfigs {
+			if data.ApplicationOf(dc.Labels) == application && (len(namespace) == 0 || dc.Namespace == namespace) {
+				addRecipients(recipients, dc.Annotations[RecipientsAnnotation])
+				for _, project := range data.Projects {
+					if project.Name == dc.Namespace {
+						addRecipients(recipients, project.Annotations[RecipientsAnnotation])
+					}
+				}
+			}
+		}
+	}
+
+	results := []string{}
+	for recipient := range recipients {
+		results = append(results, recipient)
+	}
+	sort.Strings(results)
+	return results
+}
+
+// addRecipients adds the comma-separated recipients to the given set
+func addRecipients(recipients map[string]bool, value string) {
+	for _, recipient := range strings.Split(value, ",") {
+		if recipient = strings.TrimSpace(recipient); len(recipient) > 0 {
+			recipients[recipient] = true
+		}
+	}
+}
+
+// send sends an HTML mail to the given recipients
+func (m *Mailer) send(recipients []string, subject string, body []byte) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %v\r\n", m.config.From)
+	fmt.Fprintf(&message, "To: %v\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&message, "Subject: %v\r\n", subject)
+	fmt.Fprintf(&message, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: text/html; charset=UTF-8\r\n")
+	fmt.Fprintf(&message, "\r\n")
+	message.Write(body)
+
+	client, err := m.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if len(m.config.Username) > 0 {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("The SMTP server %v doesn't support authentication", m.config.Host)
+		}
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.config.From); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message.Bytes()); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dial connects to the SMTP server, using the configured TLS mode
+func (m *Mailer) dial() (*smtp.Client, error) {
+	address := net.JoinHostPort(m.config.Host, m.config.Port)
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	if m.config.TLS == TLSModeTLS {
+		conn, err := tls.Dial("tcp", address, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		return smtp.NewClient(conn, m.config.Host)
+	}
+
+	client, err := smtp.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+	// the plain connection is only used with the "none" mode
+	if m.config.TLS == TLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("The SMTP server %v doesn't support STARTTLS: use the \"tls\" mode, or \"none\" for a plain connection", m.config.Host)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	"github.com/pmylund/go-cache"
+)
+
+const (
+	// deduplicationWindow is how long an event sent to a webhook won't be sent again
+	deduplicationWindow = 1 * time.Hour
+
+	// initialBackoff is the delay before the first retry of a failed delivery, it doubles on each retry
+	initialBackoff = 1 * time.Second
+)
+
+// Notifier detects the state transitions between consecutive refreshes of the data,
+// and sends them to the webhooks, and by mail for the critical ones.
+type Notifier struct {
+	webhooks   []Webhook
+	mailer     *Mailer
+	httpClient *http.Client
+
+	mutex    sync.Mutex
+	previous *api.Data
+
+	// sent stores the keys of the events already sent to each webhook (and by mail), to deduplicate them
+	sent *cache.Cache
+}
+
+// NewNotifier builds a new Notifier instance, for the given webhooks and mailer (which can be nil)
+func NewNotifier(webhooks []Webhook, mailer *Mailer) *Notifier {
+	return &Notifier{
+		webhooks:   webhooks,
+		mailer:     mailer,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		sent:       cache.New(deduplicationWindow, 10*time.Minute),
+	}
+}
+
+// Webhooks returns the webhooks of the notifier
+func (n *Notifier) Webhooks() []Webhook {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.webhooks
+}
+
+// SetWebhooks replaces the webhooks of the notifier
+func (n *Notifier) SetWebhooks(webhooks []Webhook) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.webhooks = webhooks
+}
+
+// Notify compares the given data with the data of the previous refresh,
+// and sends the detected events to the matching webhooks and by mail (asynchronously).
+// It can be registered as an api.RefreshListener.
+func (n *Notifier) Notify(data *api.Data, refreshedAt time.Time) {
+	n.mutex.Lock()
+	previous := n.previous
+	n.previous = data
+	webhooks := n.webhooks
+	n.mutex.Unlock()
+
+	for _, event := range DetectEvents(previous, data, refreshedAt) {
+		for i := range webhooks {
+			webhook := &webhooks[i]
+			if !webhook.Matches(event) {
+				continue
+			}
+
+			if n.alreadySent(webhook.Name + "|" + event.Key()) {
+				continue
+			}
+
+			go n.deliver(webhook, event)
+		}
+
+		if n.mailer != nil && event.IsCritical() && !n.alreadySent("mail|"+event.Key()) {
+			go n.mail(event, n.mailer.RecipientsFor(data, event.Application, event.Namespace))
+		}
+	}
+}
+
+// alreadySent returns true if an event with the given key has already been sent,
+// or records it as sent otherwise
+func (n *Notifier) alreadySent(key string) bool {
+	return n.sent.Add(key, true, cache.DefaultExpiration) != nil
+}
+
+// mail sends the given event by mail to the given recipients
+func (n *Notifier) mail(event Event, recipients []string) {
+	if err := n.mailer.SendEvent(event, recipients); err != nil {
+		log.Printf("Failed to send event %v for %v %v/%v by mail: %v", event.Type, event.Kind, event.Namespace, event.Name, err)
+	}
+}
+
+// deliver sends the given event to the given webhook,
+// and retries with an exponential backoff if it fails
+func (n *Notifier) deliver(webhook *Webhook, event Event) {
+	payload, err := webhook.Payload(event)
+	if err != nil {
+		log.Printf("Failed to build the payload of event %v for webhook %v: %v", event.Type, webhook.Name, err)
+		return
+	}
+
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		err = n.post(webhook.URL, payload)
+		if err == nil {
+			return
+		}
+		if attempt >= webhook.Retries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	log.Printf("Failed to send event %v for %v %v/%v to webhook %v: %v", event.Type, event.Kind, event.Namespace, event.Name, webhook.Name, err)
+}
+
+// post sends the given payload to the given URL
+func (n *Notifier) post(url string, payload []byte) error {
+	resp, err := n.httpClient.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the webhook answered with %v", resp.Status)
+	}
+	return nil
+}
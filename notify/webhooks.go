@@ -0,0 +1,218 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+)
+
+// Format describes the possible formats of the webhooks payloads
+type Format string
+
+const (
+	// FormatJSON is the default format: the event as JSON
+	FormatJSON Format = "json"
+
+	// FormatSlack is the format of the Slack (and Mattermost) incoming webhooks
+	FormatSlack Format = "slack"
+
+	// FormatTemplate uses a custom Go template to build the payload
+	FormatTemplate Format = "template"
+)
+
+// Webhook is an URL that receives the events, as JSON payloads
+type Webhook struct {
+	// Name identifies the webhook in the logs
+	Name string `json:"name"`
+
+	// URL is where the payloads are POSTed
+	URL string `json:"url"`
+
+	// Format is the format of the payload: "json" (the default), "slack" or "template"
+	Format Format `json:"format,omitempty"`
+
+	// Template is the Go template used to build the payload, for the "template" format.
+	// It is executed with the Event, and has a "json" function to escape the values.
+	Template string `json:"template,omitempty"`
+
+	// Channel and Username override the defaults of the Slack/Mattermost webhook, if set
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+
+	// Applications, Namespaces and Events restrict the events sent to this webhook,
+	// the empty ones match everything
+	Applications []string    `json:"applications,omitempty"`
+	Namespaces   []string    `json:"namespaces,omitempty"`
+	Events       []EventType `json:"events,omitempty"`
+
+	// Retries is the number of retries after a failed delivery (default to 3)
+	Retries int `json:"retries,omitempty"`
+
+	template *template.Template
+}
+
+// WebhooksFile is the content of a webhooks file
+type WebhooksFile struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// LoadWebhooks reads the webhooks from the given (YAML or JSON) file,
+// and validates them
+func LoadWebhooks(path string) ([]Webhook, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooksFile := &WebhooksFile{}
+	if err := yaml.Unmarshal(content, webhooksFile); err != nil {
+		return nil, fmt.Errorf("Failed to parse the webhooks file %v: %v", path, err)
+	}
+
+	for i := range webhooksFile.Webhooks {
+		if err := webhooksFile.Webhooks[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return webhooksFile.Webhooks, nil
+}
+
+// validate checks that the webhook is valid, and sets the default values
+func (w *Webhook) validate() error {
+	if len(w.URL) == 0 {
+		return fmt.Errorf("Missing URL for webhook %v!", w.Name)
+	}
+	if len(w.Name) == 0 {
+		w.Name = w.URL
+	}
+	if w.Retries == 0 {
+		w.Retries = 3
+	}
+
+	switch w.Format {
+	case "":
+		w.Format = FormatJSON
+	case FormatJSON, FormatSlack:
+	case FormatTemplate:
+		tmpl, err := template.New(w.Name).Funcs(template.FuncMap{"json": toJSON}).Parse(w.Template)
+		if err != nil {
+			return fmt.Errorf("Invalid template for webhook %v: %v", w.Name, err)
+		}
+		w.template = tmpl
+	default:
+		return fmt.Errorf("Unknown format %v for webhook %v!", w.Format, w.Name)
+	}
+
+	return nil
+}
+
+// Matches returns true if the given event should be sent to this webhook
+func (w *Webhook) Matches(event Event) bool {
+	if len(w.Applications) > 0 && !contains(w.Applications, event.Application) {
+		return false
+	}
+	if len(w.Namespaces) > 0 && !contains(w.Namespaces, event.Namespace) {
+		return false
+	}
+	if len(w.Events) > 0 {
+		found := false
+		for _, eventType := range w.Events {
+			if eventType == event.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Payload returns the payload for the given event, in the format of this webhook
+func (w *Webhook) Payload(event Event) ([]byte, error) {
+	switch w.Format {
+	case FormatSlack:
+		return json.Marshal(w.slackPayload(event))
+	case FormatTemplate:
+		var buffer bytes.Buffer
+		if err := w.template.Execute(&buffer, event); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	default:
+		return json.Marshal(event)
+	}
+}
+
+// slackMessage is the payload of a Slack/Mattermost incoming webhook
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Fallback string       `json:"fallback"`
+	Color    string       `json:"color"`
+	Fields   []slackField `json:"fields"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// slackPayload builds the Slack/Mattermost message for the given event
+func (w *Webhook) slackPayload(event Event) *slackMessage {
+	color := "good"
+	if event.IsBad() {
+		color = "danger"
+	}
+
+	fields := []slackField{}
+	if len(event.Application) > 0 {
+		fields = append(fields, slackField{Title: "Application", Value: event.Application, Short: true})
+	}
+	if len(event.Namespace) > 0 {
+		fields = append(fields, slackField{Title: "Project", Value: event.Namespace, Short: true})
+	}
+	fields = append(fields, slackField{Title: "Status", Value: event.Status, Short: true})
+	if len(event.PreviousStatus) > 0 {
+		fields = append(fields, slackField{Title: "Previous Status", Value: event.PreviousStatus, Short: true})
+	}
+
+	return &slackMessage{
+		Text:     event.Message,
+		Channel:  w.Channel,
+		Username: w.Username,
+		Attachments: []slackAttachment{{
+			Fallback: event.Message,
+			Color:    color,
+			Fields:   fields,
+		}},
+	}
+}
+
+// toJSON returns the JSON representation of the given value, to be used in the templates
+func toJSON(value interface{}) (string, error) {
+	content, err := json.Marshal(value)
+	return string(content), err
+}
+
+// contains returns true if the given value is in the given slice
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package probes
+
+import (
+	"sort"
+	"time"
+)
+
+// sparklineBuckets is the number of points of the latency sparklines, over the retention
+const sparklineBuckets = 48
+
+// Availability is the availability of a route, or of all the routes of an application, during the retention
+type Availability struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Application string `json:"application,omitempty"`
+	URL         string `json:"url,omitempty"`
+
+	// Routes is the number of routes (1 for a route)
+	Routes int `json:"routes"`
+
+	// Probes and Up are the number of probes, and of successful probes
+	Probes int `json:"probes"`
+	Up     int `json:"up"`
+
+	// Last is the result of the latest probe of a route, or nil
+	Last *Result `json:"last,omitempty"`
+
+	// Latency is the average latency of the successful probes
+	Latency time.Duration `json:"latency"`
+
+	// Sparkline is the average latency (in milliseconds) of the successful probes in each period of the retention,
+	// the oldest first, or -1 for the periods without successful probes
+	Sparkline []float64 `json:"sparkline"`
+
+	// sums and counts are the total latency and the number of successful probes of each period of the sparkline
+	sums   []time.Duration
+	counts []int
+}
+
+// Uptime returns the percentage of successful probes, or 0 if there are none
+func (a Availability) Uptime() float64 {
+	if a.Probes == 0 {
+		return 0
+	}
+	return 100 * float64(a.Up) / float64(a.Probes)
+}
+
+// newAvailability returns an empty availability
+func newAvailability() *Availability {
+	return &Availability{
+		sums:   make([]time.Duration, sparklineBuckets),
+		counts: make([]int, sparklineBuckets),
+	}
+}
+
+// add adds the given results, the oldest first, for the retention ending at the given time
+func (a *Availability) add(results []Result, now time.Time, retention time.Duration) {
+	for _, result := range results {
+		a.Probes++
+		if !result.Up {
+			continue
+		}
+		a.Up++
+		if age := now.Sub(result.Time); age >= 0 && age < retention {
+			bucket := sparklineBuckets - 1 - int(int64(age)*sparklineBuckets/int64(retention))
+			a.sums[bucket] += result.Latency
+			a.counts[bucket]++
+		}
+	}
+	if len(results) > 0 {
+		last := results[len(results)-1]
+		a.Last = &last
+	}
+}
+
+// compute computes the average latency and the sparkline, once all the results have been added
+func (a *Availability) compute() {
+	var total time.Duration
+	count := 0
+	a.Sparkline = make([]float64, sparklineBuckets)
+	for i := range a.sums {
+		a.Sparkline[i] = -1
+		if a.counts[i] > 0 {
+			a.Sparkline[i] = float64(a.sums[i]/time.Duration(a.counts[i])) / float64(time.Millisecond)
+		}
+		total += a.sums[i]
+		count += a.counts[i]
+	}
+	if count > 0 {
+		a.Latency = total / time.Duration(count)
+	}
+}
+
+// Availabilities returns the availability of the routes (sorted by namespace and name)
+// and of the applications (sorted by name), at the given time,
+// for the routes in the namespaces accepted by the given function
+func (p *Prober) Availabilities(now time.Time, accept func(namespace string) bool) ([]Availability, []Availability) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	routes := []Availability{}
+	applications := make(map[string]*Availability)
+	for _, target := range p.targets {
+		if !accept(target.Namespace) {
+			continue
+		}
+		results := p.results[target.key()]
+
+		route := newAvailability()
+		route.Namespace, route.Name, route.Application, route.URL = target.Namespace, target.Name, target.Application, target.URL
+		route.Routes = 1
+		route.add(results, now, p.config.Retention)
+		route.compute()
+		routes = append(routes, *route)
+
+		if len(target.Application) == 0 {
+			continue
+		}
+		application, found := applications[target.Application]
+		if !found {
+			application = newAvailability()
+			application.Application = target.Application
+			applications[target.Application] = application
+		}
+		application.Routes++
+		application.add(results, now, p.config.Retention)
+		// the latest results are only meaningful for the routes
+		application.Last = nil
+	}
+	sort.Sort(availabilitiesByName(routes))
+
+	names := []string{}
+	for name := range applications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	apps := []Availability{}
+	for _, name := range names {
+		applications[name].compute()
+		apps = append(apps, *applications[name])
+	}
+	return routes, apps
+}
+
+// availabilitiesByName sorts the availabilities of the routes by namespace and name
+type availabilitiesByName []Availability
+
+func (a availabilitiesByName) Len() int      { return len(a) }
+func (a availabilitiesByName) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a availabilitiesByName) Less(i, j int) bool {
+	if a[i].Namespace != a[j].Namespace {
+		return a[i].Namespace < a[j].Namespace
+	}
+	return a[i].Name < a[j].Name
+}
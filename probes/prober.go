@@ -0,0 +1,258 @@
+// Package probes provides the HTTP probes of the routes, to track their availability and latency
+// without an external monitoring stack.
+package probes
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+)
+
+// Config is the configuration of the probes
+type Config struct {
+	// Interval is the interval between two probes of a route
+	Interval time.Duration
+
+	// Timeout is the default timeout of a probe
+	Timeout time.Duration
+
+	// Workers is the maximum number of concurrent probes
+	Workers int
+
+	// Jitter is the maximum random delay before each probe, to spread the probes over time
+	Jitter time.Duration
+
+	// Retention is how long the results of the probes are kept
+	Retention time.Duration
+}
+
+// Result is the result of a probe
+type Result struct {
+	Time time.Time `json:"time"`
+
+	// Status is the HTTP status code of the response, or 0 if there was no response
+	Status int `json:"status,omitempty"`
+
+	// Latency is the time until the headers of the response have been received
+	Latency time.Duration `json:"latency"`
+
+	// TLS is the result of the TLS handshake: "ok", an error, or an empty string for the plain HTTP routes
+	TLS string `json:"tls,omitempty"`
+
+	// Error is why the probe failed, such as a connection error or an unexpected status
+	Error string `json:"error,omitempty"`
+
+	// Up is true if the route answered with an expected status
+	Up bool `json:"up"`
+}
+
+// errRedirect stops the redirects, so the probes check the status of the route itself
+var errRedirect = errors.New("redirect")
+
+// Prober periodically probes the routes, with a bounded pool of workers,
+// and keeps the results of the probes during the retention.
+type Prober struct {
+	transport *http.Transport
+
+	mutex   sync.RWMutex
+	config  Config
+	targets []Target
+	results map[string][]Result
+}
+
+// NewProber builds a new Prober instance, with the given configuration
+func NewProber(config Config) *Prober {
+	return &Prober{
+		// a new connection for each probe, to check the TLS handshake each time
+		transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DisableKeepAlives: true,
+		},
+		config:  config,
+		results: make(map[string][]Result),
+	}
+}
+
+// Config returns the configuration of the prober
+func (p *Prober) Config() Config {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.config
+}
+
+// SetConfig changes the configuration of the prober, starting with the next probes
+func (p *Prober) SetConfig(config Config) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.config = config
+}
+
+// UpdateTargets updates the routes to probe from the given data,
+// and forgets the results of the routes that are gone.
+// It can be registered as an api.RefreshListener.
+func (p *Prober) UpdateTargets(data *api.Data, refreshedAt time.Time) {
+	targets := targetsOf(data)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	results := make(map[string][]Result, len(targets))
+	for _, target := range targets {
+		results[target.key()] = p.results[target.key()]
+	}
+	p.targets = targets
+	p.results = results
+}
+
+// Targets returns the routes to probe
+func (p *Prober) Targets() []Target {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.targets
+}
+
+// Run probes the routes right away, and then at each interval, until the stop channel is closed.
+// It blocks, so it should be run in its own goroutine.
+func (p *Prober) Run(stop <-chan struct{}) {
+	for {
+		p.ProbeAll()
+
+		select {
+		case <-time.After(p.Config().Interval):
+		case <-stop:
+			return
+		}
+	}
+}
+
+// ProbeAll probes all the routes once, with at most the configured number of concurrent probes,
+// each after a random delay (the jitter), and returns when all the probes are done
+func (p *Prober) ProbeAll() {
+	config := p.Config()
+	workers := config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	targets := make(chan Target)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range targets {
+				if config.Jitter > 0 {
+					time.Sleep(time.Duration(rand.Int63n(int64(config.Jitter))))
+				}
+				p.record(target, p.probe(target, config.Timeout))
+			}
+		}()
+	}
+
+	for _, target := range p.Targets() {
+		targets <- target
+	}
+	close(targets)
+	wg.Wait()
+}
+
+// probe probes the given target, with the given timeout, or the timeout of the target if it is shorter
+// (so a route can't hold a worker longer than the others)
+func (p *Prober) probe(target Target, timeout time.Duration) Result {
+	result := Result{Time: time.Now()}
+	if len(target.Error) > 0 {
+		result.Error = target.Error
+		return result
+	}
+	if target.Timeout > 0 && target.Timeout < timeout {
+		timeout = target.Timeout
+	}
+
+	client := &http.Client{
+		Transport: p.transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return errRedirect
+		},
+	}
+	resp, err := client.Get(target.URL)
+	result.Latency = time.Since(result.Time)
+	if resp != nil {
+		// the body of a redirect has already been closed
+		resp.Body.Close()
+	}
+	if err != nil && (resp == nil || !isRedirectError(err)) {
+		result.Error = err.Error()
+		if tlsError := tlsErrorOf(err); len(tlsError) > 0 {
+			result.TLS = tlsError
+		}
+		return result
+	}
+
+	result.Status = resp.StatusCode
+	if resp.TLS != nil {
+		result.TLS = "ok"
+	}
+	if result.Up = target.isExpectedStatus(resp.StatusCode); !result.Up {
+		result.Error = fmt.Sprintf("unexpected status %v", resp.Status)
+	}
+	return result
+}
+
+// isRedirectError returns true if the given error has been returned because of a redirect
+func isRedirectError(err error) bool {
+	urlError, ok := err.(*url.Error)
+	return ok && urlError.Err == errRedirect
+}
+
+// tlsErrorOf returns the error of the TLS handshake of the given probe error, or an empty string
+func tlsErrorOf(err error) string {
+	if urlError, ok := err.(*url.Error); ok {
+		err = urlError.Err
+	}
+	switch err.(type) {
+	case x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError:
+		return err.Error()
+	}
+	if strings.HasPrefix(err.Error(), "tls: ") || strings.HasPrefix(err.Error(), "x509: ") {
+		return err.Error()
+	}
+	return ""
+}
+
+// record records the given result of the given target, and forgets the results older than the retention
+func (p *Prober) record(target Target, result Result) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	results, found := p.results[target.key()]
+	if !found {
+		// the route is gone while it was probed
+		return
+	}
+	results = append(results, result)
+	first := 0
+	for first < len(results) && results[first].Time.Before(result.Time.Add(-p.config.Retention)) {
+		first++
+	}
+	p.results[target.key()] = results[first:]
+}
+
+// Results returns the results of the probes of the given route, the oldest first
+func (p *Prober) Results(namespace string, name string) []Result {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return append([]Result{}, p.results[namespace+"/"+name]...)
+}
@@ -0,0 +1,140 @@
+package probes
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+const (
+	// ProbeAnnotation is the annotation of a route that disables its probes, with the "false" value
+	ProbeAnnotation = "openshift-dashboard/probe"
+
+	// PathAnnotation is the annotation of a route with the path to probe, such as "/healthz",
+	// instead of the path of the route
+	PathAnnotation = "openshift-dashboard/probe-path"
+
+	// ExpectedStatusAnnotation is the annotation of a route with the comma-separated expected HTTP status codes,
+	// such as "200,302" or "2xx", instead of any status below 400
+	ExpectedStatusAnnotation = "openshift-dashboard/probe-expected-status"
+
+	// TimeoutAnnotation is the annotation of a route with the timeout of its probes, such as "3s",
+	// which can only be shorter than the configured timeout
+	TimeoutAnnotation = "openshift-dashboard/probe-timeout"
+)
+
+// Target is a route to probe
+type Target struct {
+	Namespace   string
+	Name        string
+	Application string
+
+	// URL is the URL to probe, from the host and path of the route (or its path annotation)
+	URL string
+
+	// ExpectedStatus are the expected status codes (such as "200") or classes (such as "2xx"),
+	// or any status below 400 if it is empty
+	ExpectedStatus []string
+
+	// Timeout is the timeout of the probes, or the configured timeout if it is 0 (or longer)
+	Timeout time.Duration
+
+	// Error is why the target can't be probed, such as an invalid annotation
+	Error string
+}
+
+// key uniquely identifies the target
+func (t Target) key() string {
+	return t.Namespace + "/" + t.Name
+}
+
+// targetsOf returns the targets of the routes of the given data: all the routes with a host,
+// except the ones whose probes are disabled by an annotation, and the ones whose host is an IP address
+// or is outside of the allowed domains (so the projects can't probe the internal hosts of the cluster)
+func targetsOf(data *api.Data) []Target {
+	targets := []Target{}
+	for _, route := range data.Routes {
+		if len(route.Host) == 0 || route.Annotations[ProbeAnnotation] == "false" {
+			continue
+		}
+		if net.ParseIP(route.Host) != nil || len(data.RoutePolicy.AllowedDomains) == 0 || !data.RoutePolicy.IsAllowedHost(route.Host) {
+			continue
+		}
+		targets = append(targets, targetOf(route, data.ApplicationOf(route.Labels)))
+	}
+	return targets
+}
+
+// targetOf returns the target of the given route, configured by its annotations
+func targetOf(route routeapi.Route, application string) Target {
+	target := Target{
+		Namespace:   route.Namespace,
+		Name:        route.Name,
+		Application: application,
+	}
+
+	scheme, path := "http", route.Path
+	if route.TLS != nil {
+		scheme = "https"
+	}
+	if annotation := route.Annotations[PathAnnotation]; len(annotation) > 0 {
+		path = annotation
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	target.URL = scheme + "://" + route.Host + path
+
+	if annotation := route.Annotations[ExpectedStatusAnnotation]; len(annotation) > 0 {
+		for _, status := range strings.Split(annotation, ",") {
+			status = strings.ToLower(strings.TrimSpace(status))
+			if !isStatusPattern(status) {
+				target.Error = fmt.Sprintf("invalid %v annotation: %q should be a status code such as 200, or a class such as 2xx", ExpectedStatusAnnotation, status)
+				return target
+			}
+			target.ExpectedStatus = append(target.ExpectedStatus, status)
+		}
+	}
+
+	if annotation := route.Annotations[TimeoutAnnotation]; len(annotation) > 0 {
+		timeout, err := time.ParseDuration(annotation)
+		if err != nil || timeout <= 0 {
+			target.Error = fmt.Sprintf("invalid %v annotation: %q should be a duration such as 3s", TimeoutAnnotation, annotation)
+			return target
+		}
+		target.Timeout = timeout
+	}
+	return target
+}
+
+// isStatusPattern returns true if the given status is a status code (such as "200") or a class (such as "2xx")
+func isStatusPattern(status string) bool {
+	if len(status) != 3 || status[0] < '1' || status[0] > '5' {
+		return false
+	}
+	if status[1:] == "xx" {
+		return true
+	}
+	_, err := strconv.Atoi(status)
+	return err == nil
+}
+
+// isExpectedStatus returns true if the given HTTP status code is expected by the target
+func (t Target) isExpectedStatus(code int) bool {
+	if len(t.ExpectedStatus) == 0 {
+		return code < 400
+	}
+	status := strconv.Itoa(code)
+	for _, expected := range t.ExpectedStatus {
+		if expected == status || (strings.HasSuffix(expected, "xx") && expected[0] == status[0]) {
+			return true
+		}
+	}
+	return false
+}
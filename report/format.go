@@ -0,0 +1,103 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format describes the possible output formats of a report
+type Format string
+
+const (
+	// FormatTable is a table for the terminal
+	FormatTable Format = "table"
+
+	// FormatJSON is the JSON representation of the report, for the scripts
+	FormatJSON Format = "json"
+
+	// FormatMarkdown is a Markdown table, for wiki pages or pull requests
+	FormatMarkdown Format = "markdown"
+)
+
+// Write writes the report to the given writer, in the given format
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatTable:
+		return r.writeTable(w)
+	case FormatJSON:
+		return r.writeJSON(w)
+	case FormatMarkdown:
+		return r.writeMarkdown(w)
+	default:
+		return fmt.Errorf("Unknown format %v: it should be table, json or markdown", format)
+	}
+}
+
+// headers are the headers of the columns of the tables
+var headers = []string{"APPLICATION", "HEALTH", "PODS", "LAST BUILD", "LAST DEPLOYMENT", "PROJECTS", "ROUTES"}
+
+// columns returns the values of the columns of the tables, for the given application
+func columns(app ApplicationStatus) []string {
+	lastBuild := "-"
+	if app.LastBuild != nil {
+		lastBuild = fmt.Sprintf("%v (%v)", app.LastBuild.Phase, app.LastBuild.Name)
+	}
+	lastDeployment := "-"
+	if app.LastDeployment != nil {
+		lastDeployment = fmt.Sprintf("%v (%v)", app.LastDeployment.Status, app.LastDeployment.Name)
+	}
+	routes := "-"
+	if len(app.Routes) > 0 {
+		routes = strings.Join(app.Routes, ", ")
+	}
+
+	return []string{
+		app.Name,
+		string(app.Health),
+		fmt.Sprintf("%d/%d", app.ReadyPods, app.DesiredReplicas),
+		lastBuild,
+		lastDeployment,
+		strings.Join(app.Projects, ", "),
+		routes,
+	}
+}
+
+// writeTable writes the report as a table for the terminal
+func (r *Report) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, app := range r.Applications {
+		fmt.Fprintln(tw, strings.Join(columns(app), "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeJSON writes the report as JSON
+func (r *Report) writeJSON(w io.Writer) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(content))
+	return err
+}
+
+// writeMarkdown writes the report as a Markdown table
+func (r *Report) writeMarkdown(w io.Writer) error {
+	fmt.Fprintf(w, "Status of the applications at %v\n\n", r.GeneratedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "| %v |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(w, "|%v\n", strings.Repeat(" --- |", len(headers)))
+	for _, app := range r.Applications {
+		values := columns(app)
+		for i := range values {
+			values[i] = strings.Replace(values[i], "|", "\\|", -1)
+		}
+		if _, err := fmt.Fprintf(w, "| %v |\n", strings.Join(values, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package report builds a summary of the status of the applications,
+// for the command-line interface (and the scripts of the ops team).
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+)
+
+// Report is the status of all the applications at a given time
+type Report struct {
+	GeneratedAt  time.Time           `json:"generatedAt"`
+	Applications []ApplicationStatus `json:"applications"`
+}
+
+// ApplicationStatus is the status of an application, in all its projects
+type ApplicationStatus struct {
+	Name            string            `json:"name"`
+	Projects        []string          `json:"projects"`
+	Health          api.Health        `json:"health"`
+	ReadyPods       int               `json:"readyPods"`
+	DesiredReplicas int               `json:"desiredReplicas"`
+	LastBuild       *BuildStatus      `json:"lastBuild,omitempty"`
+	LastDeployment  *DeploymentStatus `json:"lastDeployment,omitempty"`
+	Routes          []string          `json:"routes"`
+}
+
+// BuildStatus is the status of a build
+type BuildStatus struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Phase     string    `json:"phase"`
+	Created   time.Time `json:"created"`
+}
+
+// DeploymentStatus is the status of a deployment (ReplicationController)
+type DeploymentStatus struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Created   time.Time `json:"created"`
+}
+
+// New builds the report of the given data, for the given applications (or for all of them if empty)
+func New(data *api.Data, applications []string, now time.Time) *Report {
+	selected := make(map[string]bool)
+	for _, application := range applications {
+		selected[application] = true
+	}
+
+	report := &Report{
+		GeneratedAt:  now,
+		Applications: []ApplicationStatus{},
+	}
+	for _, app := range data.Applications {
+		if len(selected) > 0 && !selected[app.Name()] {
+			continue
+		}
+		report.Applications = append(report.Applications, newApplicationStatus(data, app.Name()))
+	}
+	return report
+}
+
+// newApplicationStatus builds the status of the given application
+func newApplicationStatus(data *api.Data, application string) ApplicationStatus {
+	appData := data.ForApplication(application)
+	status := ApplicationStatus{
+		Name:     application,
+		Projects: []string{},
+		Health:   data.ApplicationHealthOf(application, ""),
+		Routes:   []string{},
+	}
+
+	projects := make(map[string]bool)
+	for _, dc := range appData.DeploymentConfigs {
+		projects[dc.Namespace] = true
+
+		rc := appData.LatestDeploymentOf(dc)
+		if rc == nil {
+			continue
+		}
+		status.DesiredReplicas += rc.Spec.Replicas
+		for _, pod := range data.PodsOfDeployment(*rc) {
+			if api.IsPodReady(pod) {
+				status.ReadyPods++
+			}
+		}
+	}
+	for project := range projects {
+		status.Projects = append(status.Projects, project)
+	}
+	sort.Strings(status.Projects)
+
+	for _, build := range appData.Builds {
+		if status.LastBuild == nil || build.CreationTimestamp.Time.After(status.LastBuild.Created) {
+			status.LastBuild = &BuildStatus{
+				Namespace: build.Namespace,
+				Name:      build.Name,
+				Phase:     string(build.Status.Phase),
+				Created:   build.CreationTimestamp.Time,
+			}
+		}
+	}
+
+	for _, rc := range appData.ReplicationControllers {
+		if status.LastDeployment == nil || rc.CreationTimestamp.Time.After(status.LastDeployment.Created) {
+			status.LastDeployment = &DeploymentStatus{
+				Namespace: rc.Namespace,
+				Name:      rc.Name,
+				Status:    string(api.DeploymentStatusOf(rc)),
+				Created:   rc.CreationTimestamp.Time,
+			}
+		}
+	}
+
+	for _, route := range appData.Routes {
+		status.Routes = append(status.Routes, route.Host)
+	}
+	sort.Strings(status.Routes)
+
+	return status
+}
+
+// Unhealthy returns the applications that are down, or degraded (unless the degraded applications are allowed)
+func (r *Report) Unhealthy(allowDegraded bool) []ApplicationStatus {
+	unhealthy := []ApplicationStatus{}
+	for _, app := range r.Applications {
+		if app.Health == api.HealthDown || (app.Health == api.HealthDegraded && !allowDegraded) {
+			unhealthy = append(unhealthy, app)
+		}
+	}
+	return unhealthy
+}
+
+// Has returns true if the report contains the given application
+func (r *Report) Has(application string) bool {
+	for _, app := range r.Applications {
+		if app.Name == application {
+			return true
+		}
+	}
+	return false
+}
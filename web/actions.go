@@ -0,0 +1,144 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/vbehar/openshift-dashboard/api"
+)
+
+// Action is a mutating action that can be performed from the dashboard, on a single object
+type Action struct {
+	// Name is the name of the action, used in the audit log
+	Name string
+
+	// Namespace and ObjectName identify the object on which the action is performed
+	Namespace  string
+	ObjectName string
+
+	// Details are optional details about the action, recorded in the audit log
+	Details string
+
+	// Requires are the permissions required to perform the action (all of them)
+	Requires []Requirement
+
+	// Run performs the action, and returns the URL where the user should be redirected
+	Run func(clientWrapper *api.ClientWrapper) (string, error)
+}
+
+// Requirement is a permission required to perform an action: a verb on a resource, such as "update" "deploymentconfigs"
+type Requirement struct {
+	Verb     string
+	Resource string
+}
+
+// isAllowed returns true if the user of the given ClientWrapper has all the permissions required by the given action
+func isAllowed(clientWrapper *api.ClientWrapper, action Action) (bool, error) {
+	for _, requirement := range action.Requires {
+		allowed, err := clientWrapper.CanI(action.Namespace, requirement.Verb, requirement.Resource)
+		if err != nil || !allowed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Permissions is a set of actions (by name) allowed for the current user
+type Permissions map[string]bool
+
+// Can returns true if the given action is allowed
+func (p Permissions) Can(action string) bool {
+	return p[action]
+}
+
+// permissionsFor returns the permissions of the user of the given request,
+// for the given actions (on the same namespace).
+// The permissions are always denied if the actions are disabled, or if the user is not authenticated.
+func (c *Context) permissionsFor(req *http.Request, actions ...Action) Permissions {
+	permissions := Permissions{}
+	if !c.ActionsEnabled {
+		return permissions
+	}
+	session, found := c.sessionFor(req)
+	if !found {
+		return permissions
+	}
+
+	clientWrapper := session.ClientWrapper
+	for _, action := range actions {
+		allowed, err := isAllowed(clientWrapper, action)
+		if err != nil {
+			log.Printf("Failed to check the permission for %s on %s/%s: %v", action.Name, action.Namespace, action.ObjectName, err)
+			continue
+		}
+		permissions[action.Name] = allowed
+	}
+	return permissions
+}
+
+// runAction performs the given action, if:
+// - the actions are enabled
+// - the user is authenticated
+// - the request has a valid CSRF token
+// - the user is allowed to perform the action
+// The action is recorded in the audit log, and the user is redirected
+// to the URL returned by the action.
+func (c *Context) runAction(w http.ResponseWriter, req *http.Request, action Action) {
+	if !c.ActionsEnabled {
+		http.NotFound(w, req)
+		return
+	}
+
+	// never fall back to the service account: the actions require an authenticated user
+	session, found := c.sessionFor(req)
+	if !found {
+		http.Error(w, "You must be authenticated to perform this action", http.StatusUnauthorized)
+		return
+	}
+
+	if !isValidCSRFToken(req) {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return
+	}
+
+	entry := AuditEntry{
+		User:       session.User,
+		RemoteAddr: req.RemoteAddr,
+		Action:     action.Name,
+		Namespace:  action.Namespace,
+		Name:       action.ObjectName,
+		Details:    action.Details,
+	}
+
+	clientWrapper := session.ClientWrapper
+	allowed, err := isAllowed(clientWrapper, action)
+	if err != nil || !allowed {
+		entry.Result = "denied"
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		c.AuditLog.Record(entry)
+		http.Error(w, "You are not allowed to perform this action", http.StatusForbidden)
+		return
+	}
+
+	target, err := action.Run(clientWrapper)
+	if err != nil {
+		entry.Result = "failed"
+		entry.Error = err.Error()
+		c.AuditLog.Record(entry)
+		http.Error(w, "Failed to perform the action: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	entry.Result = "success"
+	c.AuditLog.Record(entry)
+
+	http.Redirect(w, req, target, http.StatusSeeOther)
+}
+
+// objectURL returns the URL of the page that displays the given object
+func objectURL(namespace string, resource string, name string) string {
+	return "/projects/" + url.QueryEscape(namespace) + "/" + resource + "/" + url.QueryEscape(name)
+}
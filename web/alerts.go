@@ -0,0 +1,98 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/alerts"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// AlertsPage is the data exposed to the "alerts" view
+type AlertsPage struct {
+	*Page
+	Alerts         []alerts.Alert
+	Rules          []alerts.Rule
+	LastEvaluation time.Time
+}
+
+// AlertsHandler answers HTTP requests with the active and recently resolved alerts, using the "alerts" view
+func (c *Context) AlertsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	visibleAlerts, err := c.alertsFor(req, false)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	visibleRules, err := c.rulesFor(req)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	data := &AlertsPage{
+		Page:           c.NewPage(w, req),
+		Alerts:         visibleAlerts,
+		Rules:          visibleRules,
+		LastEvaluation: c.Alerts.LastEvaluation(),
+	}
+
+	c.Render.HTML(w, http.StatusOK, "alerts", data)
+}
+
+// alertsFor returns the alerts on the objects that the user of the given request can see:
+// only the active ones, or also the recently resolved ones.
+// It returns nil if there are no alerting rules.
+func (c *Context) alertsFor(req *http.Request, activeOnly bool) ([]alerts.Alert, error) {
+	if c.Alerts == nil {
+		return nil, nil
+	}
+
+	visible, err := c.visibleNamespacesFor(req)
+	if err != nil {
+		return nil, err
+	}
+
+	all := c.Alerts.Alerts()
+	if activeOnly {
+		all = c.Alerts.ActiveAlerts()
+	}
+
+	visibleAlerts := []alerts.Alert{}
+	for _, alert := range all {
+		if visible[alert.Namespace] {
+			visibleAlerts = append(visibleAlerts, alert)
+		}
+	}
+	return visibleAlerts, nil
+}
+
+// rulesFor returns the alerting rules, with only the namespaces that the user of the given request can see:
+// the rules restricted to other namespaces are not returned
+func (c *Context) rulesFor(req *http.Request) ([]alerts.Rule, error) {
+	visible, err := c.visibleNamespacesFor(req)
+	if err != nil {
+		return nil, err
+	}
+
+	visibleRules := []alerts.Rule{}
+	for _, rule := range c.Alerts.Rules() {
+		if len(rule.Namespaces) == 0 {
+			visibleRules = append(visibleRules, rule)
+			continue
+		}
+		namespaces := []string{}
+		for _, namespace := range rule.Namespaces {
+			if visible[namespace] {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+		if len(namespaces) > 0 {
+			rule.Namespaces = namespaces
+			visibleRules = append(visibleRules, rule)
+		}
+	}
+	return visibleRules, nil
+}
@@ -0,0 +1,66 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// ApplicationPage is the data exposed to the "application" view
+type ApplicationPage struct {
+	*Page
+
+	// Data contains only the objects of the application
+	*api.Data
+
+	Application api.Application
+
+	// Permissions are the permissions of the user, per namespace
+	Permissions map[string]Permissions
+
+	// BuildsTable is the table of the builds of the application
+	BuildsTable *Table
+
+	// ServicesTable is the table of the services of the application, with their misconfigurations
+	ServicesTable *Table
+
+	// PromotionsTable is the matrix of the images of the application deployed in each environment
+	PromotionsTable *Table
+}
+
+// ApplicationHandler answers HTTP requests for a single application, using the "application" view
+func (c *Context) ApplicationHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	snapshot, err := c.ClientWrapperFor(req).Snapshot()
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+	d := snapshot.Data
+
+	application := api.Application(params.ByName("name"))
+	appData := d.ForApplication(application.Name())
+
+	permissions := make(map[string]Permissions)
+	for _, dc := range appData.DeploymentConfigs {
+		if _, found := permissions[dc.Namespace]; !found {
+			permissions[dc.Namespace] = c.permissionsFor(req, deploymentConfigActions(dc.Namespace, dc.Name)...)
+		}
+	}
+
+	data := &ApplicationPage{
+		Page:        c.NewPage(w, req).withSnapshot(snapshot),
+		Data:        appData,
+		Application: application,
+		Permissions: permissions,
+		BuildsTable: buildsTable(appData, Filters{Application: application.Name()}),
+		// from all the data, to find the services without the labels of the application, and the pods of the other applications
+		ServicesTable: servicesTable(d, Filters{Application: application.Name()}),
+		// from all the data, to find the ImageStreams and builds without the labels of the application
+		PromotionsTable: promotionsTable(d, Filters{Application: application.Name()}),
+	}
+
+	c.Render.HTML(w, http.StatusOK, "application", data)
+}
@@ -0,0 +1,121 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// auditLogSize is the number of audit entries kept in memory
+	auditLogSize = 200
+)
+
+// AuditEntry is the record of an action performed from the dashboard
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user"`
+	RemoteAddr string    `json:"remoteAddr"`
+	Action     string    `json:"action"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Details    string    `json:"details,omitempty"`
+	Result     string    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// AuditLog records all the actions performed from the dashboard.
+// It keeps the most recent entries in memory, writes all of them to the logs,
+// and optionally to a file (one JSON entry per line).
+type AuditLog struct {
+	mutex   sync.RWMutex
+	entries []AuditEntry
+	file    *os.File
+}
+
+// NewAuditLog builds a new AuditLog instance
+// that will write the entries to the given file, if the path is not empty
+func NewAuditLog(path string) (*AuditLog, error) {
+	auditLog := &AuditLog{}
+	if len(path) > 0 {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, err
+		}
+		auditLog.file = file
+	}
+	return auditLog, nil
+}
+
+// Record records the given entry
+func (l *AuditLog) Record(entry AuditEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	log.Printf("AUDIT user=%q from=%s action=%s object=%s/%s details=%q result=%s %s", entry.User, entry.RemoteAddr, entry.Action, entry.Namespace, entry.Name, entry.Details, entry.Result, entry.Error)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > auditLogSize {
+		l.entries = l.entries[len(l.entries)-auditLogSize:]
+	}
+
+	if l.file != nil {
+		if line, err := json.Marshal(entry); err == nil {
+			if _, err := l.file.Write(append(line, '\n')); err != nil {
+				log.Printf("Failed to write to the audit log file: %v", err)
+			}
+		}
+	}
+}
+
+// Entries returns the entries kept in memory, the most recent first
+func (l *AuditLog) Entries() []AuditEntry {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	entries := make([]AuditEntry, len(l.entries))
+	for i, entry := range l.entries {
+		entries[len(l.entries)-1-i] = entry
+	}
+	return entries
+}
+
+// AuditPage is the data exposed to the "audit" view
+type AuditPage struct {
+	*Page
+	Entries []AuditEntry
+}
+
+// AuditHandler answers HTTP requests with the most recent actions, using the "audit" view
+func (c *Context) AuditHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	visible, err := c.visibleNamespacesFor(req)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	// only show the actions performed on the namespaces the user can see
+	entries := []AuditEntry{}
+	for _, entry := range c.AuditLog.Entries() {
+		if visible[entry.Namespace] {
+			entries = append(entries, entry)
+		}
+	}
+
+	data := &AuditPage{
+		Page:    c.NewPage(w, req),
+		Entries: entries,
+	}
+
+	c.Render.HTML(w, http.StatusOK, "audit", data)
+}
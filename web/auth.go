@@ -0,0 +1,44 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/config"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Authenticator authenticates the users of the dashboard.
+// It is a negroni middleware, that rejects (or redirects) the unauthenticated requests.
+type Authenticator interface {
+	// ServeHTTP is the negroni middleware
+	ServeHTTP(w http.ResponseWriter, req *http.Request, next http.HandlerFunc)
+
+	// RegisterHandlers registers the handlers required by the authentication flow, if any
+	RegisterHandlers(router *httprouter.Router)
+
+	// Session returns the session of the user authenticated by the given request
+	Session(req *http.Request) (*Session, bool)
+
+	// LogoutURL returns the URL used to log out, or an empty string if the users can't log out
+	LogoutURL() string
+}
+
+// NewAuthenticator builds a new Authenticator for the given authentication mode:
+// "oauth" (OpenShift OAuth Server) or "proxy" (authenticating reverse proxy).
+// It returns nil if the mode is empty, meaning that the authentication is disabled
+// (and everybody sees what the service account can see).
+func NewAuthenticator(conf config.AuthConfig, clientWrapper *api.ClientWrapper) (Authenticator, error) {
+	switch conf.Mode {
+	case "":
+		return nil, nil
+	case "oauth":
+		return NewOAuthAuthenticator(conf.OAuth, clientWrapper)
+	case "proxy":
+		return NewProxyAuthenticator(conf.Proxy, clientWrapper)
+	default:
+		return nil, fmt.Errorf("Unknown authentication mode %v", conf.Mode)
+	}
+}
@@ -0,0 +1,145 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/probes"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// AvailabilityPage is the data exposed to the "availability" view
+type AvailabilityPage struct {
+	*Page
+	Filters Filters
+
+	// Projects are the possible values of the project filter
+	Projects []string
+
+	// Config is the configuration of the probes
+	Config probes.Config
+
+	// RoutesTable and ApplicationsTable are the availability of the routes, and of the applications
+	RoutesTable       *Table
+	ApplicationsTable *Table
+}
+
+// AvailabilityHandler answers HTTP requests for the availability of the routes, from their probes,
+// using the "availability" view. Only the routes of the projects the user can see are displayed.
+// The "format" query parameter can be used to export the routes in "csv" or "json", instead of the view.
+func (c *Context) AvailabilityHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	visible, err := c.visibleNamespacesFor(req)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	filters := filtersFor(req)
+	routes, applications := c.Prober.Availabilities(time.Now(), func(namespace string) bool {
+		return visible[namespace] && filters.Matches(namespace)
+	})
+	table := routesAvailabilityTable(routes, filters)
+
+	switch format := req.URL.Query().Get("format"); format {
+	case "":
+		data := &AvailabilityPage{
+			Page:              c.NewPage(w, req),
+			Filters:           filters,
+			Config:            c.Prober.Config(),
+			RoutesTable:       table,
+			ApplicationsTable: applicationsAvailabilityTable(applications, filters),
+		}
+		projects := make(map[string]bool)
+		for _, target := range c.Prober.Targets() {
+			if visible[target.Namespace] && !projects[target.Namespace] {
+				projects[target.Namespace] = true
+				data.Projects = append(data.Projects, target.Namespace)
+			}
+		}
+		sort.Strings(data.Projects)
+		c.Render.HTML(w, http.StatusOK, "availability", data)
+	default:
+		c.exportTable(w, table, format)
+	}
+}
+
+// routesAvailabilityTable builds the table of the availability of the routes,
+// with the result of their latest probe
+func routesAvailabilityTable(routes []probes.Availability, filters Filters) *Table {
+	table := &Table{
+		Name:    "availability",
+		Title:   "Routes",
+		Icon:    "fa-globe",
+		Filters: filters,
+		Headers: []string{"Project", "Route", "Application", "URL", "Status", "TLS", "Last probe", "Uptime", "Latency", "Latency trend"},
+		Rows:    [][]Cell{},
+	}
+	for _, route := range routes {
+		row := []Cell{
+			{Value: route.Namespace},
+			{Value: route.Name},
+			applicationCell(route.Application),
+			{Value: route.URL, Link: route.URL},
+			{},
+			{},
+			{},
+		}
+		if last := route.Last; last != nil {
+			status := "down"
+			if last.Status > 0 {
+				status = fmt.Sprintf("%d", last.Status)
+			}
+			row[4] = Cell{Value: status, Highlight: !last.Up, Title: last.Error}
+			row[5] = Cell{Value: last.TLS, Highlight: len(last.TLS) > 0 && last.TLS != "ok"}
+			row[6] = Cell{Value: last.Time.Format(timeFormat)}
+		}
+		table.Rows = append(table.Rows, append(row, availabilityCells(route)...))
+	}
+	return table
+}
+
+// applicationsAvailabilityTable builds the table of the availability of the applications (all their routes)
+func applicationsAvailabilityTable(applications []probes.Availability, filters Filters) *Table {
+	table := &Table{
+		Title:   "Applications",
+		Icon:    "fa-list-alt",
+		Filters: filters,
+		Headers: []string{"Application", "Routes", "Uptime", "Latency", "Latency trend"},
+		Rows:    [][]Cell{},
+	}
+	for _, application := range applications {
+		row := []Cell{
+			applicationCell(application.Application),
+			{Value: fmt.Sprintf("%d", application.Routes)},
+		}
+		table.Rows = append(table.Rows, append(row, availabilityCells(application)...))
+	}
+	return table
+}
+
+// availabilityCells returns the cells of the uptime, the average latency and the latency trend of the given availability
+func availabilityCells(availability probes.Availability) []Cell {
+	if availability.Probes == 0 {
+		return []Cell{{}, {}, {}}
+	}
+	trend := ""
+	for i, latency := range availability.Sparkline {
+		if i > 0 {
+			trend += " "
+		}
+		if latency < 0 {
+			trend += "-"
+		} else {
+			trend += fmt.Sprintf("%.0f", latency)
+		}
+	}
+	return []Cell{
+		{Value: fmt.Sprintf("%.2f%%", availability.Uptime()), Highlight: availability.Up < availability.Probes,
+			Title: fmt.Sprintf("%d of %d probes successful", availability.Up, availability.Probes)},
+		{Value: fmt.Sprintf("%dms", availability.Latency/time.Millisecond)},
+		{Value: trend, Sparkline: availability.Sparkline, Title: "Average latency (in ms) of the successful probes"},
+	}
+}
@@ -0,0 +1,143 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// BuildConfigPage is the data exposed to the "buildconfig" view
+type BuildConfigPage struct {
+	*Page
+	BuildConfig buildapi.BuildConfig
+	Builds      []buildapi.Build
+	Permissions Permissions
+}
+
+// BuildPage is the data exposed to the "build" view
+type BuildPage struct {
+	*Page
+	Build       buildapi.Build
+	Permissions Permissions
+}
+
+// BuildConfigHandler answers HTTP requests for a single BuildConfig, using the "buildconfig" view
+func (c *Context) BuildConfigHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	d, err := c.ClientWrapperFor(req).LoadData(api.ResourceTypeBuildConfig, api.ResourceTypeBuild)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	bc, found := d.FindBuildConfig(params.ByName("namespace"), params.ByName("name"))
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+
+	data := &BuildConfigPage{
+		Page:        c.NewPage(w, req),
+		BuildConfig: *bc,
+		Builds:      d.BuildsOf(*bc),
+		Permissions: c.permissionsFor(req,
+			startBuildAction(bc.Namespace, bc.Name),
+			rerunBuildAction(bc.Namespace, ""),
+			cancelBuildAction(bc.Namespace, ""),
+		),
+	}
+
+	c.Render.HTML(w, http.StatusOK, "buildconfig", data)
+}
+
+// BuildHandler answers HTTP requests for a single Build, using the "build" view
+func (c *Context) BuildHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	d, err := c.ClientWrapperFor(req).LoadData(api.ResourceTypeBuild)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	build, found := d.FindBuild(params.ByName("namespace"), params.ByName("name"))
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+
+	data := &BuildPage{
+		Page:  c.NewPage(w, req),
+		Build: *build,
+		Permissions: c.permissionsFor(req,
+			rerunBuildAction(build.Namespace, build.Name),
+			cancelBuildAction(build.Namespace, build.Name),
+		),
+	}
+
+	c.Render.HTML(w, http.StatusOK, "build", data)
+}
+
+// StartBuildHandler starts a new build from a BuildConfig
+func (c *Context) StartBuildHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	c.runAction(w, req, startBuildAction(params.ByName("namespace"), params.ByName("name")))
+}
+
+// RerunBuildHandler starts a new build, with the same configuration as an existing build
+func (c *Context) RerunBuildHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	c.runAction(w, req, rerunBuildAction(params.ByName("namespace"), params.ByName("name")))
+}
+
+// CancelBuildHandler cancels a running build
+func (c *Context) CancelBuildHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	c.runAction(w, req, cancelBuildAction(params.ByName("namespace"), params.ByName("name")))
+}
+
+func startBuildAction(namespace string, name string) Action {
+	return Action{
+		Name:       "start-build",
+		Namespace:  namespace,
+		ObjectName: name,
+		Requires:   []Requirement{{Verb: "create", Resource: "buildconfigs/instantiate"}},
+		Run: func(clientWrapper *api.ClientWrapper) (string, error) {
+			build, err := clientWrapper.StartBuild(namespace, name)
+			if err != nil {
+				return "", err
+			}
+			return objectURL(namespace, "builds", build.Name), nil
+		},
+	}
+}
+
+func rerunBuildAction(namespace string, name string) Action {
+	return Action{
+		Name:       "rerun-build",
+		Namespace:  namespace,
+		ObjectName: name,
+		Requires:   []Requirement{{Verb: "create", Resource: "builds/clone"}},
+		Run: func(clientWrapper *api.ClientWrapper) (string, error) {
+			build, err := clientWrapper.RerunBuild(namespace, name)
+			if err != nil {
+				return "", err
+			}
+			return objectURL(namespace, "builds", build.Name), nil
+		},
+	}
+}
+
+func cancelBuildAction(namespace string, name string) Action {
+	return Action{
+		Name:       "cancel-build",
+		Namespace:  namespace,
+		ObjectName: name,
+		Requires:   []Requirement{{Verb: "update", Resource: "builds"}},
+		Run: func(clientWrapper *api.ClientWrapper) (string, error) {
+			if err := clientWrapper.CancelBuild(namespace, name); err != nil {
+				return "", err
+			}
+			return objectURL(namespace, "builds", name), nil
+		},
+	}
+}
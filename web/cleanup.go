@@ -0,0 +1,175 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// CleanupPage is the data exposed to the "cleanup" view
+type CleanupPage struct {
+	*Page
+	Filters Filters
+
+	// Projects are the possible values of the project filter
+	Projects []string
+
+	// StaleBuildConfigDays and BuildsRetention describe when the BuildConfigs and the builds are considered as unused
+	StaleBuildConfigDays int
+	BuildsRetention      int
+
+	// SummaryTable is the number of cleanup candidates of each kind, per project
+	SummaryTable *Table
+
+	// Reports are the cleanup candidates of each project
+	Reports []CleanupReport
+}
+
+// CleanupReport is the cleanup report of a project
+type CleanupReport struct {
+	Project string
+
+	// Table is the table of the cleanup candidates of the project
+	Table *Table
+
+	// Commands are the "oc delete" commands that delete the cleanup candidates of the project
+	Commands []string
+}
+
+// CleanupHandler answers HTTP requests for the resources that are probably garbage, using the "cleanup" view:
+// a report per project, with the suggested "oc delete" commands.
+// The "format" query parameter can be used to export the candidates in "csv" or "json", instead of the view.
+func (c *Context) CleanupHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	snapshot, err := c.ClientWrapperFor(req).Snapshot()
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+	d := snapshot.Data
+
+	filters := filtersFor(req)
+	candidates := []api.CleanupCandidate{}
+	for _, candidate := range d.CleanupCandidates(time.Now()) {
+		if filters.Matches(candidate.Namespace) {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	switch format := req.URL.Query().Get("format"); format {
+	case "":
+		data := &CleanupPage{
+			Page:         c.NewPage(w, req).withSnapshot(snapshot),
+			Filters:      filters,
+			SummaryTable: cleanupSummaryTable(candidates, filters),
+		}
+		data.StaleBuildConfigDays, data.BuildsRetention = d.CleanupPolicy.Thresholds()
+		for _, project := range d.Projects {
+			data.Projects = append(data.Projects, project.Name)
+		}
+		sort.Strings(data.Projects)
+		for i := 0; i < len(candidates); {
+			// the candidates are sorted by project
+			j := i
+			for j < len(candidates) && candidates[j].Namespace == candidates[i].Namespace {
+				j++
+			}
+			report := CleanupReport{
+				Project: candidates[i].Namespace,
+				Table:   cleanupTable(candidates[i:j], Filters{Project: candidates[i].Namespace}),
+			}
+			report.Table.Title = report.Project
+			for _, candidate := range candidates[i:j] {
+				if command := candidate.Command(); len(command) > 0 {
+					report.Commands = append(report.Commands, command)
+				}
+			}
+			data.Reports = append(data.Reports, report)
+			i = j
+		}
+		c.Render.HTML(w, http.StatusOK, "cleanup", data)
+	default:
+		c.exportTable(w, cleanupTable(candidates, filters), format)
+	}
+}
+
+// cleanupTable builds the table of the given cleanup candidates, with their "oc delete" commands
+func cleanupTable(candidates []api.CleanupCandidate, filters Filters) *Table {
+	table := &Table{
+		Name:    "cleanup",
+		Title:   "Cleanup",
+		Icon:    "fa-trash",
+		Filters: filters,
+		Headers: []string{"Project", "Kind", "Name", "Reason", "Command"},
+		Rows:    [][]Cell{},
+	}
+	for _, candidate := range candidates {
+		table.Rows = append(table.Rows, []Cell{
+			{Value: candidate.Namespace},
+			{Value: candidate.Kind},
+			{Value: candidate.Name, Link: cleanupCandidateURL(candidate)},
+			{Value: candidate.Reason},
+			{Value: candidate.Command()},
+		})
+	}
+	return table
+}
+
+// cleanupSummaryTable builds the table of the number of cleanup candidates of each kind, per project
+// (and if the project itself is a candidate, as an unused or idle project)
+func cleanupSummaryTable(candidates []api.CleanupCandidate, filters Filters) *Table {
+	table := &Table{
+		Title:   "Projects",
+		Icon:    "fa-folder-open",
+		Filters: filters,
+		Headers: []string{"Project", "Unused project", "Idle project"},
+		Rows:    [][]Cell{},
+	}
+	kinds := []string{}
+	for _, kind := range api.CleanupKinds {
+		if kind != "Project" && kind != "IdleProject" {
+			kinds = append(kinds, kind)
+		}
+	}
+	table.Headers = append(table.Headers, kinds...)
+	counts := make(map[string]map[string]int)
+	projects := []string{}
+	for _, candidate := range candidates {
+		if _, found := counts[candidate.Namespace]; !found {
+			counts[candidate.Namespace] = make(map[string]int)
+			projects = append(projects, candidate.Namespace)
+		}
+		counts[candidate.Namespace][candidate.Kind]++
+	}
+	for _, project := range projects {
+		unused, idle := counts[project]["Project"] > 0, counts[project]["IdleProject"] > 0
+		row := []Cell{
+			{Value: project, Link: "#cleanup-" + project},
+			{Value: strconv.FormatBool(unused), Highlight: unused},
+			{Value: strconv.FormatBool(idle), Highlight: idle},
+		}
+		for _, kind := range kinds {
+			count := counts[project][kind]
+			row = append(row, Cell{Value: strconv.Itoa(count), Highlight: count > 0})
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// cleanupCandidateURL returns the URL of the page of the given cleanup candidate, or an empty string if it has no page
+func cleanupCandidateURL(candidate api.CleanupCandidate) string {
+	switch candidate.Kind {
+	case "BuildConfig":
+		return fmt.Sprintf("/projects/%s/buildconfigs/%s", candidate.Namespace, candidate.Name)
+	case "Build":
+		return fmt.Sprintf("/projects/%s/builds/%s", candidate.Namespace, candidate.Name)
+	default:
+		return ""
+	}
+}
@@ -2,10 +2,18 @@ package web
 
 import (
 	"html/template"
-	"os"
-	"strings"
+	"log"
+	"net/http"
+	"sync"
+	"time"
 
+	"github.com/vbehar/openshift-dashboard/alerts"
 	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/assets"
+	"github.com/vbehar/openshift-dashboard/config"
+	"github.com/vbehar/openshift-dashboard/history"
+	"github.com/vbehar/openshift-dashboard/notify"
+	"github.com/vbehar/openshift-dashboard/probes"
 
 	"github.com/thoas/stats"
 	"github.com/unrolled/render"
@@ -16,37 +24,216 @@ type Context struct {
 	ClientWrapper *api.ClientWrapper
 	Render        *render.Render
 	Stats         *stats.Stats
+
+	// Assets are the templates and the static files, embedded in the binary or read from the disk
+	Assets *assets.Assets
+
+	// Authenticator authenticates the users, or is nil if authentication is disabled
+	Authenticator Authenticator
+
+	// ActionsEnabled is true if the mutating actions (start a build, ...) are enabled
+	ActionsEnabled bool
+
+	// AuditLog records the actions performed by the users
+	AuditLog *AuditLog
+
+	// Refresher periodically refreshes the data (with the service account's credentials)
+	// for the background tasks, such as the alerting rules, and for the pages of the anonymous users
+	Refresher *api.Refresher
+
+	// Alerts evaluates the alerting rules on each refresh, or is nil if there are no rules
+	Alerts *alerts.Engine
+
+	// Notifier sends the state transitions to the webhooks, or is nil if there are no webhooks
+	Notifier *notify.Notifier
+
+	// History records the history of the resources on each refresh, or is nil if it is disabled
+	History *history.Store
+
+	// Prober probes the routes, or is nil if the probes are disabled
+	Prober *probes.Prober
+
+	configMutex sync.RWMutex
+	config      *config.Config
+
+	// configFile is the path of the configuration file, reloaded on SIGHUP
+	configFile string
 }
 
-// NewContext builds a new Context instance
-func NewContext() *Context {
+// NewContext builds a new Context instance, with the given configuration
+func NewContext(conf *config.Config, configFile string) *Context {
+	var c *Context
 	s := stats.New()
 
+	a := assets.New(conf.IsDevEnv(), conf.Server.PublicDir, conf.Branding.OverridesDir)
 	r := render.New(render.Options{
-		IsDevelopment: isDevEnv(),
-		Funcs: []template.FuncMap{{
-			"filterByNamespace":   api.FilterByNamespace,
-			"filterByApplication": api.FilterByApplication,
-			"filterByLabelValue":  api.FilterByLabelValue,
-		}},
+		IsDevelopment: conf.IsDevEnv(),
+		Directory:     assets.TemplatesDir,
+		Asset:         a.TemplateAsset,
+		AssetNames:    a.TemplateNames,
+		Layout:        "layout",
+		Funcs: []template.FuncMap{templateFuncs(a, func() api.Grouping {
+			// the context is built below, before any template is rendered
+			return c.Config().ClientOptions().Grouping
+		})},
 	})
 
-	cacheEnabled := !isDevEnv()
-	clientWrapper := api.NewClientWrapper(cacheEnabled)
+	cacheEnabled := !conf.IsDevEnv() && conf.Cache.ResourcesTTL > 0
+	clientWrapper, err := api.NewClientWrapper(cacheEnabled, conf.Connection(), conf.ClientOptions())
+	if err != nil {
+		log.Fatalf("Failed to connect to the cluster: %v", err)
+	}
+
+	authenticator, err := NewAuthenticator(conf.Auth, clientWrapper)
+	if err != nil {
+		log.Fatalf("Failed to initialize the authentication: %v", err)
+	}
+
+	actionsEnabled := conf.Actions.Enabled
+	if actionsEnabled && authenticator == nil {
+		// without authentication, the actions would be performed by anyone, with the service account
+		log.Printf("The actions are disabled, because they require an authentication mode")
+		actionsEnabled = false
+	}
+
+	auditLog, err := NewAuditLog(conf.Actions.AuditLogFile)
+	if err != nil {
+		log.Fatalf("Failed to open the audit log file: %v", err)
+	}
+
+	refresher := clientWrapper.NewRefresher(time.Duration(conf.Refresh.Interval))
+
+	var alertsEngine *alerts.Engine
+	if rulesFile := conf.Alerts.RulesFile; len(rulesFile) > 0 {
+		rules, err := alerts.LoadRules(rulesFile)
+		if err != nil {
+			log.Fatalf("Failed to load the alerting rules: %v", err)
+		}
+		alertsEngine = alerts.NewEngine(rules)
+		refresher.AddListener(alertsEngine.Evaluate)
+		log.Printf("Loaded %d alerting rules from %v", len(rules), rulesFile)
+	}
+
+	webhooks := []notify.Webhook{}
+	if webhooksFile := conf.Notifications.WebhooksFile; len(webhooksFile) > 0 {
+		webhooks, err = notify.LoadWebhooks(webhooksFile)
+		if err != nil {
+			log.Fatalf("Failed to load the webhooks: %v", err)
+		}
+		log.Printf("Loaded %d webhooks from %v", len(webhooks), webhooksFile)
+	}
+
+	mailer, err := newMailer(conf, a)
+	if err != nil {
+		log.Fatalf("Failed to initialize the mails: %v", err)
+	}
+
+	var notifier *notify.Notifier
+	if len(webhooks) > 0 || mailer != nil {
+		notifier = notify.NewNotifier(webhooks, mailer)
+		refresher.AddListener(notifier.Notify)
+	}
+
+	if mailer != nil {
+		hour, minute, enabled, err := parseDigestTime(conf.Notifications.DigestTime)
+		if err != nil {
+			log.Fatalf("Failed to schedule the daily digest: %v", err)
+		}
+		if enabled {
+			go mailer.RunDigests(refresher, hour, minute)
+		}
+	}
+
+	var historyStore *history.Store
+	if historyDir := conf.History.Dir; len(historyDir) > 0 {
+		retention := time.Duration(conf.History.Retention)
+		historyStore, err = history.NewStore(historyDir, retention, time.Duration(conf.History.DownsampleAfter))
+		if err != nil {
+			log.Fatalf("Failed to open the history store: %v", err)
+		}
+		refresher.AddListener(historyStore.Record)
+		log.Printf("Recording the history in %v, for %v", historyDir, retention)
+	}
+
+	var prober *probes.Prober
+	if conf.Probes.Enabled {
+		prober = probes.NewProber(conf.ProberConfig())
+		refresher.AddListener(prober.UpdateTargets)
+		log.Printf("Probing the routes every %v", time.Duration(conf.Probes.Interval))
+	}
+
+	c = &Context{
+		ClientWrapper:  clientWrapper,
+		Render:         r,
+		Stats:          s,
+		Assets:         a,
+		Authenticator:  authenticator,
+		ActionsEnabled: actionsEnabled,
+		AuditLog:       auditLog,
+		Refresher:      refresher,
+		Alerts:         alertsEngine,
+		Notifier:       notifier,
+		History:        historyStore,
+		Prober:         prober,
+		config:         conf,
+		configFile:     configFile,
+	}
+	return c
+}
+
+// Config returns the current configuration
+func (c *Context) Config() *config.Config {
+	c.configMutex.RLock()
+	defer c.configMutex.RUnlock()
+
+	return c.config
+}
+
+// ClientWrapperFor returns the ClientWrapper that should be used to answer the given request:
+// the one of the authenticated user if there is one, or the default one.
+func (c *Context) ClientWrapperFor(req *http.Request) *api.ClientWrapper {
+	if session, found := c.sessionFor(req); found {
+		return session.ClientWrapper
+	}
+	return c.ClientWrapper
+}
+
+// UserFor returns the name of the authenticated user for the given request,
+// or an empty string if there is none
+func (c *Context) UserFor(req *http.Request) string {
+	if session, found := c.sessionFor(req); found {
+		return session.User
+	}
+	return ""
+}
+
+// LogoutURL returns the URL used by the authenticated users to log out,
+// or an empty string if they can't log out
+func (c *Context) LogoutURL() string {
+	if c.Authenticator == nil {
+		return ""
+	}
+	return c.Authenticator.LogoutURL()
+}
+
+// visibleNamespacesFor returns the namespaces that the user of the given request can see
+func (c *Context) visibleNamespacesFor(req *http.Request) (map[string]bool, error) {
+	namespaces, err := c.ClientWrapperFor(req).GetAvailableNamespaces()
+	if err != nil {
+		return nil, err
+	}
 
-	return &Context{
-		ClientWrapper: clientWrapper,
-		Render:        r,
-		Stats:         s,
+	visible := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		visible[namespace] = true
 	}
+	return visible, nil
 }
 
-// isDevEnv returns true if we are running in "dev" env
-// It checks the value of the GO_ENV env var (it should be equals to "dev")
-func isDevEnv() bool {
-	goEnv := os.Getenv("GO_ENV")
-	if strings.ToLower(goEnv) == "dev" {
-		return true
+// sessionFor returns the session of the authenticated user for the given request, if any
+func (c *Context) sessionFor(req *http.Request) (*Session, bool) {
+	if c.Authenticator == nil {
+		return nil, false
 	}
-	return false
+	return c.Authenticator.Session(req)
 }
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+const (
+	// CSRFCookieName is the name of the cookie used to store the CSRF token
+	CSRFCookieName = "openshift-dashboard-csrf"
+
+	// CSRFFormField is the name of the form field that must contain the CSRF token
+	CSRFFormField = "csrf_token"
+)
+
+// csrfToken returns the CSRF token for the given request.
+// It uses the token stored in the CSRF cookie, or generates a new one (and sets the cookie).
+func csrfToken(w http.ResponseWriter, req *http.Request) string {
+	if cookie, err := req.Cookie(CSRFCookieName); err == nil && len(cookie.Value) > 0 {
+		return cookie.Value
+	}
+
+	token, err := randomString(32)
+	if err != nil {
+		return ""
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   isSecureRequest(req),
+	})
+	return token
+}
+
+// isValidCSRFToken returns true if the given request has a valid CSRF token:
+// the token sent with the form must be the same as the one stored in the cookie
+func isValidCSRFToken(req *http.Request) bool {
+	cookie, err := req.Cookie(CSRFCookieName)
+	if err != nil || len(cookie.Value) == 0 {
+		return false
+	}
+
+	token := req.PostFormValue(CSRFFormField)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(cookie.Value)) == 1
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/dashboard"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// DashboardPage is the data exposed to the "dashboard" view
+type DashboardPage struct {
+	*Page
+	Dashboard *dashboard.Dashboard
+}
+
+// DashboardHandler answers HTTP requests for a custom dashboard, using the "dashboard" view
+func (c *Context) DashboardHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	definition, found := dashboard.Find(c.Config().Dashboards, params.ByName("name"))
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+
+	c.renderDashboard(w, req, definition)
+}
+
+// renderDashboard renders the given dashboard, with the data that the user of the given request can see
+func (c *Context) renderDashboard(w http.ResponseWriter, req *http.Request, definition dashboard.Definition) {
+	snapshot, err := c.ClientWrapperFor(req).Snapshot()
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+	d := snapshot.Data
+
+	activeAlerts, err := c.alertsFor(req, true)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	data := &DashboardPage{
+		Page:      c.NewPage(w, req).withSnapshot(snapshot),
+		Dashboard: definition.Evaluate(d, activeAlerts, time.Now()),
+	}
+
+	c.Render.HTML(w, http.StatusOK, "dashboard", data)
+}
+
+// panelTable returns the table of the given (table) panel:
+// the number of objects per group if it has a groupBy field, or the objects otherwise
+func panelTable(panel dashboard.Panel) *Table {
+	table := &Table{
+		Title: panel.Title,
+		Icon:  resourceIcon(panel.Resource),
+		Rows:  [][]Cell{},
+	}
+
+	if len(panel.GroupBy) > 0 {
+		table.Headers = []string{panel.GroupBy, "Count"}
+		for _, group := range panel.Groups {
+			table.Rows = append(table.Rows, []Cell{
+				{Value: group.Label},
+				{Value: strconv.Itoa(group.Value)},
+			})
+		}
+		return table
+	}
+
+	table.Headers = []string{"Project", "Name", "Application", "Status", "Created"}
+	for _, object := range panel.Objects {
+		created := ""
+		if !object.Created.IsZero() {
+			created = object.Created.Format(timeFormat)
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Value: object.Namespace},
+			{Value: object.Name, Link: panelObjectURL(object)},
+			applicationCell(object.Application),
+			{Value: object.Status, Label: len(object.Status) > 0},
+			{Value: created},
+		})
+	}
+	return table
+}
+
+// panelObjectURL returns the URL of the page of the given object, or an empty string if it has no page
+func panelObjectURL(object dashboard.Object) string {
+	switch object.Kind {
+	case "Application":
+		return "/applications/" + object.Name
+	case "BuildConfig":
+		return objectURL(object.Namespace, "buildconfigs", object.Name)
+	case "Build":
+		return objectURL(object.Namespace, "builds", object.Name)
+	case "DeploymentConfig":
+		return objectURL(object.Namespace, "deploymentconfigs", object.Name)
+	default:
+		return ""
+	}
+}
+
+// resourceIcon returns the font-awesome icon of the given resource type
+func resourceIcon(resourceType api.ResourceType) string {
+	switch resourceType {
+	case api.ResourceTypeApplication:
+		return "fa-home"
+	case api.ResourceTypeProject:
+		return "fa-folder-open"
+	case api.ResourceTypeRoute:
+		return "fa-road"
+	case api.ResourceTypeService:
+		return "fa-sitemap"
+	case api.ResourceTypeEndpoints:
+		return "fa-plug"
+	case api.ResourceTypePod:
+		return "fa-gears"
+	case api.ResourceTypeImageStream:
+		return "fa-file-text-o"
+	case api.ResourceTypeBuildConfig:
+		return "fa-cogs"
+	case api.ResourceTypeBuild:
+		return "fa-gear"
+	case api.ResourceTypeDeploymentConfig:
+		return "fa-cloud-upload"
+	case api.ResourceTypeReplicationController:
+		return "fa-history"
+	case api.ResourceTypeEvent:
+		return "fa-bolt"
+	default:
+		return "fa-bell"
+	}
+}
@@ -0,0 +1,141 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	deployapi "github.com/openshift/origin/pkg/deploy/api"
+
+	"github.com/julienschmidt/httprouter"
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// DeploymentConfigPage is the data exposed to the "deploymentconfig" view
+type DeploymentConfigPage struct {
+	*Page
+	DeploymentConfig deployapi.DeploymentConfig
+	Deployments      []kapi.ReplicationController
+	LatestDeployment *kapi.ReplicationController
+	Permissions      Permissions
+}
+
+// DeploymentConfigHandler answers HTTP requests for a single DeploymentConfig, using the "deploymentconfig" view
+func (c *Context) DeploymentConfigHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	d, err := c.ClientWrapperFor(req).LoadData(api.ResourceTypeDeploymentConfig, api.ResourceTypeReplicationController)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	dc, found := d.FindDeploymentConfig(params.ByName("namespace"), params.ByName("name"))
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+
+	data := &DeploymentConfigPage{
+		Page:             c.NewPage(w, req),
+		DeploymentConfig: *dc,
+		Deployments:      d.DeploymentsOf(*dc),
+		LatestDeployment: d.LatestDeploymentOf(*dc),
+		Permissions:      c.permissionsFor(req, deploymentConfigActions(dc.Namespace, dc.Name)...),
+	}
+
+	c.Render.HTML(w, http.StatusOK, "deploymentconfig", data)
+}
+
+// ScaleDeploymentConfigHandler scales a DeploymentConfig to the number of replicas given in the form,
+// up to the configured maximum
+func (c *Context) ScaleDeploymentConfigHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	replicas, err := strconv.ParseUint(req.PostFormValue("replicas"), 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid number of replicas", http.StatusBadRequest)
+		return
+	}
+	if max := c.Config().Actions.MaxReplicas; replicas > uint64(max) {
+		http.Error(w, fmt.Sprintf("Too many replicas: at most %d", max), http.StatusBadRequest)
+		return
+	}
+
+	c.runAction(w, req, scaleDeploymentConfigAction(params.ByName("namespace"), params.ByName("name"), uint(replicas)))
+}
+
+// RedeployDeploymentConfigHandler triggers a new deployment of a DeploymentConfig
+func (c *Context) RedeployDeploymentConfigHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	c.runAction(w, req, redeployDeploymentConfigAction(params.ByName("namespace"), params.ByName("name")))
+}
+
+// RollbackDeploymentConfigHandler rolls back a DeploymentConfig to the deployment given in the form
+func (c *Context) RollbackDeploymentConfigHandler(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+	deployment := req.PostFormValue("deployment")
+	if len(deployment) == 0 {
+		http.Error(w, "Missing deployment", http.StatusBadRequest)
+		return
+	}
+
+	c.runAction(w, req, rollbackDeploymentConfigAction(params.ByName("namespace"), params.ByName("name"), deployment))
+}
+
+// deploymentConfigActions returns all the actions that can be performed on a DeploymentConfig,
+// used to check the permissions
+func deploymentConfigActions(namespace string, name string) []Action {
+	return []Action{
+		scaleDeploymentConfigAction(namespace, name, 0),
+		redeployDeploymentConfigAction(namespace, name),
+		rollbackDeploymentConfigAction(namespace, name, ""),
+	}
+}
+
+func scaleDeploymentConfigAction(namespace string, name string, replicas uint) Action {
+	return Action{
+		Name:       "scale",
+		Namespace:  namespace,
+		ObjectName: name,
+		Details:    fmt.Sprintf("replicas=%d", replicas),
+		Requires:   []Requirement{{Verb: "update", Resource: "replicationcontrollers"}},
+		Run: func(clientWrapper *api.ClientWrapper) (string, error) {
+			if err := clientWrapper.ScaleDeploymentConfig(namespace, name, replicas); err != nil {
+				return "", err
+			}
+			return objectURL(namespace, "deploymentconfigs", name), nil
+		},
+	}
+}
+
+func redeployDeploymentConfigAction(namespace string, name string) Action {
+	return Action{
+		Name:       "redeploy",
+		Namespace:  namespace,
+		ObjectName: name,
+		Requires:   []Requirement{{Verb: "update", Resource: "deploymentconfigs"}},
+		Run: func(clientWrapper *api.ClientWrapper) (string, error) {
+			if err := clientWrapper.RedeployDeploymentConfig(namespace, name); err != nil {
+				return "", err
+			}
+			return objectURL(namespace, "deploymentconfigs", name), nil
+		},
+	}
+}
+
+func rollbackDeploymentConfigAction(namespace string, name string, deployment string) Action {
+	return Action{
+		Name:       "rollback",
+		Namespace:  namespace,
+		ObjectName: name,
+		Details:    "to=" + deployment,
+		// the rollback is generated by a DeploymentConfigRollback, and then applied to the DeploymentConfig
+		Requires: []Requirement{
+			{Verb: "create", Resource: "deploymentconfigrollbacks"},
+			{Verb: "update", Resource: "deploymentconfigs"},
+		},
+		Run: func(clientWrapper *api.ClientWrapper) (string, error) {
+			if err := clientWrapper.RollbackDeploymentConfig(namespace, name, deployment); err != nil {
+				return "", err
+			}
+			return objectURL(namespace, "deploymentconfigs", name), nil
+		},
+	}
+}
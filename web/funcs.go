@@ -0,0 +1,117 @@
+package web
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/assets"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+)
+
+// templateFuncs returns the functions that can be used in the templates,
+// with the given assets for the URLs of the static files, and the given function for the current grouping
+// (with the application labels of the configuration).
+// The "filterBy*" functions scan the given objects, and are kept for the custom templates:
+// the "for*" functions use the indexes of a Data instance, and return a new Data instance.
+func templateFuncs(a *assets.Assets, grouping func() api.Grouping) template.FuncMap {
+	return template.FuncMap{
+		"asset":             a.URL,
+		"filterByNamespace": api.FilterByNamespace,
+		"filterByApplication": func(objects interface{}, application string) ([]interface{}, error) {
+			return grouping().FilterByApplication(objects, application)
+		},
+		"filterByLabelValue":  api.FilterByLabelValue,
+		"forNamespace":        (*api.Data).ForNamespace,
+		"forApplication":      (*api.Data).ForApplication,
+		"forLabelValue":       (*api.Data).ForLabelValue,
+		"filterBySelector":    api.FilterBySelector,
+		"filterByFields":      api.FilterByFields,
+		"buildConfigNameOf":   api.BuildConfigNameOf,
+		"isBuildFinished":     api.IsBuildFinished,
+		"deploymentStatusOf":  api.DeploymentStatusOf,
+		"deploymentVersionOf": api.DeploymentVersionOf,
+		"statusLabel":         statusLabel,
+		"resourceIcon":        resourceIcon,
+		"panelTable":          panelTable,
+		"commitURL":           commitURL,
+		"sparkline":           sparkline,
+	}
+}
+
+// statusLabel returns the CSS class of the bootstrap label
+// used to display the given status (of a build, a deployment, ...)
+func statusLabel(status interface{}) string {
+	switch fmt.Sprintf("%v", status) {
+	case "Complete", "success", "resolved":
+		return "label-success"
+	case "Running":
+		return "label-primary"
+	case "New", "Pending":
+		return "label-info"
+	case "Failed", "Error", "failed", "denied", "firing":
+		return "label-danger"
+	case "Cancelled", "pending":
+		return "label-warning"
+	default:
+		return "label-default"
+	}
+}
+
+// sparkline returns an inline SVG image of the line of the given values (such as latencies), scaled to its maximum value.
+// The negative values are missing values, which interrupt the line.
+func sparkline(values []float64) template.HTML {
+	const width, height = 120, 20
+	if len(values) < 2 {
+		return ""
+	}
+	max := 0.0
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+
+	lines := []string{}
+	points := []string{}
+	for i, value := range values {
+		if value < 0 || max == 0 {
+			if len(points) > 0 {
+				lines = append(lines, strings.Join(points, " "))
+				points = []string{}
+			}
+			continue
+		}
+		x := float64(i) * width / float64(len(values)-1)
+		y := height - 1 - value*(height-2)/max
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	if len(points) > 0 {
+		lines = append(lines, strings.Join(points, " "))
+	}
+
+	svg := fmt.Sprintf(`<svg class="sparkline" width="%d" height="%d">`, width, height)
+	for _, line := range lines {
+		if !strings.Contains(line, " ") {
+			// a single point
+			line += " " + line
+		}
+		svg += fmt.Sprintf(`<polyline points="%s" fill="none" stroke="#337ab7" stroke-width="1.5" stroke-linecap="round"/>`, line)
+	}
+	return template.HTML(svg + "</svg>")
+}
+
+// commitURL returns the URL of the given commit in the web interface of the Git repository of the given source,
+// such as "https://github.com/user/repo/commit/1234", or an empty string if the repository is not an HTTP(S) one
+func commitURL(source buildapi.BuildSource, commit string) string {
+	if source.Git == nil || len(commit) == 0 {
+		return ""
+	}
+	uri := source.Git.URI
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(uri, "/"), ".git") + "/commit/" + commit
+}
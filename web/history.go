@@ -0,0 +1,76 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/history"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// TrendsPage is the data exposed to the "trends" view
+type TrendsPage struct {
+	*Page
+	Since    time.Time
+	Trend    []history.TrendPoint
+	Activity []history.DailyActivity
+}
+
+// TrendsHandler answers HTTP requests with the trends of the recorded history, using the "trends" view
+func (c *Context) TrendsHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	period := c.History.Retention()
+	if days := req.URL.Query().Get("days"); len(days) > 0 {
+		var n int
+		if _, err := fmt.Sscanf(days, "%d", &n); err != nil || n <= 0 {
+			http.Error(w, fmt.Sprintf("Invalid number of days %v!", days), http.StatusBadRequest)
+			return
+		}
+		period = time.Duration(n) * 24 * time.Hour
+	}
+	since := time.Now().Add(-period)
+
+	visible, err := c.visibleNamespacesFor(req)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	trend, err := c.History.DailyTrend(since, visible)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	activity, err := c.History.DailyActivity(since, visible)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	data := &TrendsPage{
+		Page:     c.NewPage(w, req),
+		Since:    since,
+		Trend:    trend,
+		Activity: activity,
+	}
+
+	c.Render.HTML(w, http.StatusOK, "trends", data)
+}
+
+// activityFor returns the recent daily activity (kept in memory by the history), in the namespaces
+// that the user of the given request can see.
+// It returns nil if the history is disabled.
+func (c *Context) activityFor(req *http.Request) ([]history.DailyActivity, error) {
+	if c.History == nil {
+		return nil, nil
+	}
+
+	visible, err := c.visibleNamespacesFor(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.History.RecentActivity(visible), nil
+}
@@ -3,9 +3,11 @@ package web
 import (
 	"fmt"
 	"net/http"
-	"os"
 
+	"github.com/vbehar/openshift-dashboard/alerts"
 	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/dashboard"
+	"github.com/vbehar/openshift-dashboard/history"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -13,29 +15,53 @@ import (
 // Data represents the data retrieved from the API, and exposed to view
 type Data struct {
 	*api.Data
-}
+	*Page
 
-// Title returns the title of the page
-// using either the env var DASHBOARD_TITLE
-// or a default title.
-func (d *Data) Title() string {
-	if title := os.Getenv("DASHBOARD_TITLE"); len(title) > 0 {
-		return title
-	}
+	// Alerts are the active alerts on the objects the user can see
+	Alerts []alerts.Alert
+
+	// Activity is the recorded daily activity of the builds and deployments, or nil if there is no history
+	Activity []history.DailyActivity
 
-	// default value
-	return "openshift-dashboard"
+	// ApplicationsTable is the table of the applications
+	ApplicationsTable *Table
 }
 
-// HomeHandler answers HTTP requests by loading data for all resource types and using the "home" view
+// HomeHandler answers HTTP requests by loading data for all resource types and using the "home" view,
+// or renders the "home" custom dashboard instead, if it is defined
 func (c *Context) HomeHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
-	d, err := c.ClientWrapper.LoadData(api.ResourceTypeAll...)
+	if definition, found := dashboard.Find(c.Config().Dashboards, dashboard.HomeDashboard); found {
+		c.renderDashboard(w, req, definition)
+		return
+	}
+
+	snapshot, err := c.ClientWrapperFor(req).Snapshot()
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+	d := snapshot.Data
+
+	activeAlerts, err := c.alertsFor(req, true)
 	if err != nil {
 		fmt.Fprintf(w, "failed to load data: %v", err)
 		return
 	}
 
-	data := &Data{d}
+	activity, err := c.activityFor(req)
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+
+	data := &Data{
+		Data:     d,
+		Page:     c.NewPage(w, req).withSnapshot(snapshot),
+		Alerts:   activeAlerts,
+		Activity: activity,
+
+		ApplicationsTable: applicationsTable(d, Filters{}),
+	}
 
 	c.Render.HTML(w, http.StatusOK, "home", data)
 }
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/assets"
+	"github.com/vbehar/openshift-dashboard/config"
+	"github.com/vbehar/openshift-dashboard/notify"
+)
+
+// newMailer builds a new Mailer instance, using the SMTP settings of the given configuration,
+// and the templates of the given assets.
+// It returns nil if there is no SMTP host, meaning that the mails are disabled.
+func newMailer(conf *config.Config, a *assets.Assets) (*notify.Mailer, error) {
+	smtp := conf.Notifications.SMTP
+	if len(smtp.Host) == 0 {
+		return nil, nil
+	}
+
+	return notify.NewMailer(notify.MailerConfig{
+		Host:         smtp.Host,
+		Port:         smtp.Port,
+		TLS:          notify.TLSMode(smtp.TLS),
+		Username:     smtp.Username,
+		Password:     smtp.Password,
+		From:         smtp.From,
+		Recipients:   smtp.Recipients,
+		Title:        conf.Branding.Title,
+		DashboardURL: strings.TrimSuffix(conf.Server.PublicURL, "/"),
+	}, a.Template)
+}
+
+// parseDigestTime parses the time of the daily digest, such as "08:00".
+// It returns false if the digest is disabled.
+func parseDigestTime(value string) (hour int, minute int, enabled bool, err error) {
+	if value == "disabled" {
+		return 0, 0, false, nil
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("Invalid digest time %v: it should be such as 08:00", value)
+	}
+	return t.Hour(), t.Minute(), true, nil
+}
@@ -0,0 +1,201 @@
+package web
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/config"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pmylund/go-cache"
+)
+
+const (
+	// OAuthPathPrefix is the prefix of all the paths used by the OAuth flow
+	OAuthPathPrefix = "/oauth/"
+
+	// OAuthStateCookieName is the name of the cookie that binds the OAuth "state" to the browser
+	// which started the authentication
+	OAuthStateCookieName = "openshift-dashboard-oauth-state"
+
+	// oauthStateExpiration is how long an authentication can be pending
+	oauthStateExpiration = 10 * time.Minute
+)
+
+// OAuthAuthenticator authenticates users with the OpenShift OAuth Server.
+// Each authenticated user gets its own session, with its own ClientWrapper,
+// so that the users only see what they have access to.
+type OAuthAuthenticator struct {
+	client        *api.OAuthClient
+	clientWrapper *api.ClientWrapper
+	sessions      *SessionStore
+
+	// states stores the OAuth "state" parameters of the pending authentications,
+	// with the URL the user was trying to reach
+	states *cache.Cache
+}
+
+// NewOAuthAuthenticator builds a new OAuthAuthenticator instance,
+// with the given configuration of the OAuth client
+func NewOAuthAuthenticator(conf config.OAuthConfig, clientWrapper *api.ClientWrapper) (*OAuthAuthenticator, error) {
+	if len(conf.ClientSecret) == 0 {
+		return nil, fmt.Errorf("Missing OAuth client secret!")
+	}
+	if len(conf.RedirectURI) == 0 {
+		return nil, fmt.Errorf("Missing OAuth redirect URI!")
+	}
+
+	client, err := clientWrapper.NewOAuthClient(
+		conf.ClientID,
+		conf.ClientSecret,
+		conf.RedirectURI,
+		conf.ServerURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthAuthenticator{
+		client:        client,
+		clientWrapper: clientWrapper,
+		sessions:      NewSessionStore(),
+		states:        cache.New(oauthStateExpiration, 1*time.Minute),
+	}, nil
+}
+
+// RegisterHandlers registers the handlers used by the OAuth flow
+func (a *OAuthAuthenticator) RegisterHandlers(router *httprouter.Router) {
+	router.GET(OAuthPathPrefix+"callback", a.CallbackHandler)
+	router.GET(OAuthPathPrefix+"logout", a.LogoutHandler)
+}
+
+// Session returns the session of the user authenticated by the given request
+func (a *OAuthAuthenticator) Session(req *http.Request) (*Session, bool) {
+	return a.sessions.Get(req)
+}
+
+// LogoutURL returns the URL used to log out
+func (a *OAuthAuthenticator) LogoutURL() string {
+	return OAuthPathPrefix + "logout"
+}
+
+// ServeHTTP is a negroni middleware that redirects unauthenticated users to the OAuth Server
+func (a *OAuthAuthenticator) ServeHTTP(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	if strings.HasPrefix(req.URL.Path, OAuthPathPrefix) {
+		next(w, req)
+		return
+	}
+
+	if _, found := a.sessions.Get(req); found {
+		next(w, req)
+		return
+	}
+
+	if req.Method != "GET" {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	state, err := randomString(16)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	a.states.Set(state, req.URL.RequestURI(), cache.DefaultExpiration)
+	http.SetCookie(w, &http.Cookie{
+		Name:     OAuthStateCookieName,
+		Value:    state,
+		Path:     OAuthPathPrefix,
+		Expires:  time.Now().Add(oauthStateExpiration),
+		HttpOnly: true,
+		Secure:   isSecureRequest(req),
+	})
+
+	http.Redirect(w, req, a.client.AuthorizeURL(state), http.StatusFound)
+}
+
+// CallbackHandler handles the redirection from the OAuth Server, once the user is authenticated:
+// it exchanges the authorization code for an access token, and starts a new session.
+func (a *OAuthAuthenticator) CallbackHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	params := req.URL.Query()
+	if errorCode := params.Get("error"); len(errorCode) > 0 {
+		http.Error(w, "Authentication failed: "+errorCode+" "+params.Get("error_description"), http.StatusUnauthorized)
+		return
+	}
+
+	// the state must have been issued to this browser, so that no one can log a user in with their own account
+	state := params.Get("state")
+	cookie, err := req.Cookie(OAuthStateCookieName)
+	http.SetCookie(w, &http.Cookie{
+		Name:     OAuthStateCookieName,
+		Value:    "",
+		Path:     OAuthPathPrefix,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isSecureRequest(req),
+	})
+	if err != nil || len(state) == 0 || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		http.Error(w, "Authentication failed: the state doesn't match the one of this browser", http.StatusBadRequest)
+		return
+	}
+
+	target, found := a.states.Get(state)
+	if !found {
+		http.Error(w, "Authentication failed: invalid or expired state", http.StatusBadRequest)
+		return
+	}
+	a.states.Delete(state)
+
+	token, err := a.client.ExchangeCode(params.Get("code"))
+	if err != nil {
+		log.Printf("OAuth authentication failed: %v", err)
+		http.Error(w, "Authentication failed", http.StatusUnauthorized)
+		return
+	}
+
+	clientWrapper, err := a.clientWrapper.ForToken(token.AccessToken)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	user, err := clientWrapper.CurrentUser()
+	if err != nil {
+		log.Printf("Failed to retrieve the authenticated user: %v", err)
+		http.Error(w, "Authentication failed", http.StatusUnauthorized)
+		return
+	}
+
+	expiresAfter := token.ExpiresAfter()
+	if expiresAfter <= 0 {
+		expiresAfter = 24 * time.Hour
+	}
+
+	session := &Session{
+		User:          user.Name,
+		ClientWrapper: clientWrapper,
+	}
+	if err := a.sessions.New(w, req, session, expiresAfter); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("User %s logged in", user.Name)
+	http.Redirect(w, req, target.(string), http.StatusFound)
+}
+
+// LogoutHandler ends the session of the current user
+func (a *OAuthAuthenticator) LogoutHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	if session, found := a.sessions.Get(req); found {
+		log.Printf("User %s logged out", session.User)
+	}
+	a.sessions.Delete(w, req)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("You have been logged out."))
+}
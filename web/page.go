@@ -0,0 +1,87 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/config"
+	"github.com/vbehar/openshift-dashboard/dashboard"
+)
+
+// Page contains what is common to all the pages rendered with the layout
+type Page struct {
+	// User is the name of the authenticated user, if any
+	User string
+
+	// LogoutURL is the URL used by the authenticated user to log out, if any
+	LogoutURL string
+
+	// CSRFToken is the token that must be sent back with the forms, to perform actions
+	CSRFToken string
+
+	// ActionsEnabled is true if the mutating actions are enabled
+	ActionsEnabled bool
+
+	// MaxReplicas is the maximum number of replicas a DeploymentConfig can be scaled to
+	MaxReplicas int
+
+	// AlertsEnabled is true if there are alerting rules
+	AlertsEnabled bool
+
+	// HistoryEnabled is true if the history of the resources is recorded
+	HistoryEnabled bool
+
+	// ProbesEnabled is true if the routes are probed
+	ProbesEnabled bool
+
+	// DataLoadedAt is the time the displayed data were loaded, if the page displays a snapshot of the data
+	DataLoadedAt time.Time
+
+	// branding is the look of the dashboard
+	branding config.BrandingConfig
+
+	// dashboards are the custom dashboards, for the navbar
+	dashboards []dashboard.Definition
+}
+
+// NewPage builds a new Page instance, for the given request
+func (c *Context) NewPage(w http.ResponseWriter, req *http.Request) *Page {
+	conf := c.Config()
+	page := &Page{
+		User:           c.UserFor(req),
+		LogoutURL:      c.LogoutURL(),
+		ActionsEnabled: c.ActionsEnabled,
+		MaxReplicas:    conf.Actions.MaxReplicas,
+		AlertsEnabled:  c.Alerts != nil,
+		HistoryEnabled: c.History != nil,
+		ProbesEnabled:  c.Prober != nil,
+		branding:       conf.Branding,
+		dashboards:     conf.Dashboards,
+	}
+	if c.ActionsEnabled {
+		page.CSRFToken = csrfToken(w, req)
+	}
+	return page
+}
+
+// withSnapshot records the time the data of the given snapshot were loaded, and returns the page
+func (p *Page) withSnapshot(snapshot *api.Snapshot) *Page {
+	p.DataLoadedAt = snapshot.LoadedAt
+	return p
+}
+
+// Title returns the title of the page
+func (p *Page) Title() string {
+	return p.branding.Title
+}
+
+// Branding returns the look of the dashboard: logo, colors, links, ...
+func (p *Page) Branding() config.BrandingConfig {
+	return p.branding
+}
+
+// Dashboards returns the custom dashboards
+func (p *Page) Dashboards() []dashboard.Definition {
+	return p.dashboards
+}
@@ -0,0 +1,170 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+	"github.com/vbehar/openshift-dashboard/config"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pmylund/go-cache"
+)
+
+// ProxyAuthenticator authenticates users with the headers set by an authenticating reverse proxy.
+// The headers are only trusted if the request comes from one of the trusted sources.
+// The data are retrieved with the service account's credentials,
+// but only from the projects the user (or one of its groups) has access to.
+type ProxyAuthenticator struct {
+	clientWrapper  *api.ClientWrapper
+	userHeader     string
+	groupsHeader   string
+	trustedSources []*net.IPNet
+
+	// sessions stores a session per identity (user and groups)
+	sessions *cache.Cache
+}
+
+// NewProxyAuthenticator builds a new ProxyAuthenticator instance,
+// with the given configuration
+func NewProxyAuthenticator(conf config.ProxyAuthConfig, clientWrapper *api.ClientWrapper) (*ProxyAuthenticator, error) {
+	trustedSources, err := parseTrustedSources(conf.TrustedSources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProxyAuthenticator{
+		clientWrapper:  clientWrapper,
+		userHeader:     conf.UserHeader,
+		groupsHeader:   conf.GroupsHeader,
+		trustedSources: trustedSources,
+		sessions:       cache.New(1*time.Hour, 10*time.Minute),
+	}, nil
+}
+
+// RegisterHandlers does nothing: there is no authentication flow to handle,
+// it is all done by the proxy
+func (a *ProxyAuthenticator) RegisterHandlers(router *httprouter.Router) {
+}
+
+// Session returns the session of the user authenticated by the given request
+func (a *ProxyAuthenticator) Session(req *http.Request) (*Session, bool) {
+	identity, ok := a.identityFor(req)
+	if !ok {
+		return nil, false
+	}
+
+	key := identity.User + "|" + strings.Join(identity.Groups, ",")
+	if session, found := a.sessions.Get(key); found {
+		// keep the session of the active users
+		a.sessions.Set(key, session, cache.DefaultExpiration)
+		return session.(*Session), true
+	}
+
+	session := &Session{
+		ID:            key,
+		User:          identity.User,
+		ClientWrapper: a.clientWrapper.ForIdentity(*identity),
+	}
+	a.sessions.Set(key, session, cache.DefaultExpiration)
+	return session, true
+}
+
+// LogoutURL returns an empty string: the users can't log out from the dashboard,
+// the authentication is handled by the proxy
+func (a *ProxyAuthenticator) LogoutURL() string {
+	return ""
+}
+
+// ServeHTTP is a negroni middleware that rejects the requests without a trusted identity
+func (a *ProxyAuthenticator) ServeHTTP(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	if !a.isTrustedSource(req) {
+		log.Printf("Rejecting request from untrusted source %s", req.RemoteAddr)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if _, ok := a.identityFor(req); !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	next(w, req)
+}
+
+// identityFor returns the identity set by the proxy on the given request,
+// or false if the request doesn't have a trusted identity
+func (a *ProxyAuthenticator) identityFor(req *http.Request) (*api.Identity, bool) {
+	if !a.isTrustedSource(req) {
+		return nil, false
+	}
+
+	user := strings.TrimSpace(req.Header.Get(a.userHeader))
+	if len(user) == 0 {
+		return nil, false
+	}
+
+	groups := []string{}
+	for _, group := range strings.Split(req.Header.Get(a.groupsHeader), ",") {
+		if group = strings.TrimSpace(group); len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+
+	return &api.Identity{
+		User:   user,
+		Groups: groups,
+	}, true
+}
+
+// isTrustedSource returns true if the given request comes from one of the trusted sources
+func (a *ProxyAuthenticator) isTrustedSource(req *http.Request) bool {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, source := range a.trustedSources {
+		if source.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedSources parses a list of IPs or CIDRs
+func parseTrustedSources(sources []string) ([]*net.IPNet, error) {
+	trustedSources := []*net.IPNet{}
+	for _, source := range sources {
+		source = strings.TrimSpace(source)
+		if len(source) == 0 {
+			continue
+		}
+
+		if !strings.Contains(source, "/") {
+			if ip := net.ParseIP(source); ip != nil && ip.To4() != nil {
+				source += "/32"
+			} else {
+				source += "/128"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(source)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid trusted source %v: %v", source, err)
+		}
+		trustedSources = append(trustedSources, ipNet)
+	}
+	return trustedSources, nil
+}
@@ -0,0 +1,120 @@
+package web
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/alerts"
+	"github.com/vbehar/openshift-dashboard/config"
+	"github.com/vbehar/openshift-dashboard/notify"
+)
+
+// ReloadConfigOnSignal reloads the configuration each time the process receives a SIGHUP signal.
+// It blocks, so it should be run in its own goroutine.
+func (c *Context) ReloadConfigOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		if err := c.ReloadConfig(); err != nil {
+			log.Printf("Failed to reload the configuration, keeping the current one: %v", err)
+		}
+	}
+}
+
+// ReloadConfig reads the configuration again, and applies the settings that can be changed while running:
+// the caches, the refresh interval, the projects and resource types, the grouping, the branding (except the overrides directory),
+// the alerting rules, the webhooks and the probes (except their activation).
+// The other settings are only applied after a restart.
+// If the new configuration is invalid, the current one is kept.
+func (c *Context) ReloadConfig() error {
+	conf, err := config.Load(c.configFile)
+	if err != nil {
+		return err
+	}
+
+	var rules []alerts.Rule
+	if c.Alerts != nil && len(conf.Alerts.RulesFile) > 0 {
+		if rules, err = alerts.LoadRules(conf.Alerts.RulesFile); err != nil {
+			return err
+		}
+	}
+
+	webhooks := []notify.Webhook{}
+	if c.Notifier != nil && len(conf.Notifications.WebhooksFile) > 0 {
+		if webhooks, err = notify.LoadWebhooks(conf.Notifications.WebhooksFile); err != nil {
+			return err
+		}
+	}
+
+	c.ClientWrapper.SetOptions(conf.ClientOptions())
+	c.Refresher.SetInterval(time.Duration(conf.Refresh.Interval))
+	if rules != nil {
+		c.Alerts.SetRules(rules)
+	}
+	if c.Notifier != nil {
+		c.Notifier.SetWebhooks(webhooks)
+	}
+	if c.Prober != nil {
+		c.Prober.SetConfig(conf.ProberConfig())
+	}
+
+	c.configMutex.Lock()
+	previous := c.config
+	c.config = conf
+	c.configMutex.Unlock()
+
+	if changed := restartRequiredChanges(previous, conf, c); len(changed) > 0 {
+		log.Printf("The changes of the %v settings will only be applied after a restart", strings.Join(changed, ", "))
+	}
+	log.Printf("Reloaded the configuration")
+	return nil
+}
+
+// restartRequiredChanges returns the names of the settings that changed
+// between the given configurations, but can't be applied while running
+func restartRequiredChanges(previous *config.Config, current *config.Config, c *Context) []string {
+	changed := []string{}
+	if !reflect.DeepEqual(previous.Server, current.Server) {
+		changed = append(changed, "server")
+	}
+	if !reflect.DeepEqual(previous.Cluster, current.Cluster) {
+		changed = append(changed, "cluster")
+	}
+	if (previous.Cache.ResourcesTTL > 0) != (current.Cache.ResourcesTTL > 0) {
+		changed = append(changed, "cache.resourcesTTL")
+	}
+	if previous.Branding.OverridesDir != current.Branding.OverridesDir {
+		changed = append(changed, "branding.overridesDir")
+	}
+	if !reflect.DeepEqual(previous.Auth, current.Auth) {
+		changed = append(changed, "auth")
+	}
+	if !reflect.DeepEqual(previous.Actions, current.Actions) {
+		changed = append(changed, "actions")
+	}
+	if (c.Alerts != nil) != (len(current.Alerts.RulesFile) > 0) {
+		changed = append(changed, "alerts.rulesFile")
+	}
+	if c.Notifier == nil && len(current.Notifications.WebhooksFile) > 0 {
+		changed = append(changed, "notifications.webhooksFile")
+	}
+	if !reflect.DeepEqual(previous.Notifications.SMTP, current.Notifications.SMTP) {
+		changed = append(changed, "notifications.smtp")
+	}
+	if previous.Notifications.DigestTime != current.Notifications.DigestTime {
+		changed = append(changed, "notifications.digestTime")
+	}
+	if !reflect.DeepEqual(previous.History, current.History) {
+		changed = append(changed, "history")
+	}
+	if (c.Prober != nil) != current.Probes.Enabled {
+		changed = append(changed, "probes.enabled")
+	}
+	return changed
+}
@@ -0,0 +1,106 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	"github.com/pmylund/go-cache"
+)
+
+const (
+	// SessionCookieName is the name of the cookie used to store the session ID
+	SessionCookieName = "openshift-dashboard-session"
+)
+
+// Session holds the state of an authenticated user
+type Session struct {
+	ID string
+
+	// User is the name of the authenticated user
+	User string
+
+	// ClientWrapper is used to retrieve data from the API, on behalf of the user
+	ClientWrapper *api.ClientWrapper
+}
+
+// SessionStore stores the sessions in memory
+type SessionStore struct {
+	sessions *cache.Cache
+}
+
+// NewSessionStore builds a new SessionStore instance
+func NewSessionStore() *SessionStore {
+	return &SessionStore{
+		sessions: cache.New(24*time.Hour, 10*time.Minute),
+	}
+}
+
+// Get returns the session associated with the given request (using the session cookie)
+func (s *SessionStore) Get(req *http.Request) (*Session, bool) {
+	cookie, err := req.Cookie(SessionCookieName)
+	if err != nil || len(cookie.Value) == 0 {
+		return nil, false
+	}
+
+	session, found := s.sessions.Get(cookie.Value)
+	if !found {
+		return nil, false
+	}
+	return session.(*Session), true
+}
+
+// New stores a new session that will expire after the given duration,
+// and sets the session cookie on the response
+func (s *SessionStore) New(w http.ResponseWriter, req *http.Request, session *Session, expiresAfter time.Duration) error {
+	id, err := randomString(32)
+	if err != nil {
+		return err
+	}
+	session.ID = id
+
+	s.sessions.Set(session.ID, session, expiresAfter)
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    session.ID,
+		Path:     "/",
+		Expires:  time.Now().Add(expiresAfter),
+		HttpOnly: true,
+		Secure:   isSecureRequest(req),
+	})
+	return nil
+}
+
+// Delete removes the session associated with the given request,
+// and clears the session cookie
+func (s *SessionStore) Delete(w http.ResponseWriter, req *http.Request) {
+	if session, found := s.Get(req); found {
+		s.sessions.Delete(session.ID)
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isSecureRequest(req),
+	})
+}
+
+// randomString returns a random hex-encoded string, built from the given number of random bytes
+func randomString(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// isSecureRequest returns true if the given request has been received over HTTPS,
+// either directly or through a proxy (such as the OpenShift router)
+func isSecureRequest(req *http.Request) bool {
+	return req.TLS != nil || req.Header.Get("X-Forwarded-Proto") == "https"
+}
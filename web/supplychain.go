@@ -0,0 +1,150 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// SupplyChainPage is the data exposed to the "supplychain" view
+type SupplyChainPage struct {
+	*Page
+	Filters Filters
+
+	// Images are the images of the supply chain, to complete the image filter
+	Images []string
+
+	// Projects are the possible values of the project filter
+	Projects []string
+
+	// ImpactsTable is the table of the objects impacted by an update of the image, if there is an image filter
+	ImpactsTable *Table
+
+	// BrokenTable is the table of the broken links of the supply chain
+	BrokenTable *Table
+
+	// Rebuilds and Redeploys are the number of BuildConfigs that rebuild
+	// and of DeploymentConfigs that redeploy (automatically or not) when the image is updated
+	Rebuilds  int
+	Redeploys int
+}
+
+// SupplyChainHandler answers HTTP requests for the supply chain of the images, using the "supplychain" view:
+// the objects impacted by an update of the image of the "image" query parameter, and the broken links.
+// The "format" query parameter can be used to export the impacts in "csv" or "json", instead of the view.
+func (c *Context) SupplyChainHandler(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+	snapshot, err := c.ClientWrapperFor(req).Snapshot()
+	if err != nil {
+		fmt.Fprintf(w, "failed to load data: %v", err)
+		return
+	}
+	d := snapshot.Data
+
+	// the supply chain crosses the applications (and the labels): only the project filter is applied, on the rows
+	filters := filtersFor(req)
+	sc := d.SupplyChain()
+	impacts := []api.Impact{}
+	if len(filters.Image) > 0 {
+		impacts = sc.Impacts(filters.Image)
+	}
+	table := impactsTable(impacts, filters)
+
+	switch format := req.URL.Query().Get("format"); format {
+	case "":
+		data := &SupplyChainPage{
+			Page:         c.NewPage(w, req).withSnapshot(snapshot),
+			Filters:      filters,
+			Images:       sc.Images(""),
+			BrokenTable:  brokenLinksTable(sc.Broken, filters),
+			ImpactsTable: table,
+		}
+		for _, project := range d.Projects {
+			data.Projects = append(data.Projects, project.Name)
+		}
+		sort.Strings(data.Projects)
+		for _, impact := range impacts {
+			switch impact.Kind {
+			case "BuildConfig":
+				data.Rebuilds++
+			case "DeploymentConfig":
+				data.Redeploys++
+			}
+		}
+		c.Render.HTML(w, http.StatusOK, "supplychain", data)
+	default:
+		c.exportTable(w, table, format)
+	}
+}
+
+// impactsTable builds the table of the objects impacted by an update of an image, by depth
+func impactsTable(impacts []api.Impact, filters Filters) *Table {
+	table := &Table{
+		Name:    "supplychain",
+		Title:   "Impacts",
+		Icon:    "fa-sitemap",
+		Filters: filters,
+		Headers: []string{"Depth", "Kind", "Project", "Name", "Image", "Path", "Update"},
+		Rows:    [][]Cell{},
+	}
+	for _, impact := range impacts {
+		if !filters.Matches(impact.Namespace) {
+			continue
+		}
+		update := "manual"
+		if impact.Automatic {
+			update = "automatic"
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Value: strconv.Itoa(impact.Depth)},
+			{Value: impact.Kind},
+			{Value: impact.Namespace},
+			{Value: impact.Name, Link: consumerURL(impact.ImageConsumer)},
+			{Value: impact.Image},
+			{Value: strings.Join(impact.Path, " → ")},
+			{Value: update, Highlight: !impact.Automatic},
+		})
+	}
+	return table
+}
+
+// brokenLinksTable builds the table of the broken links of the supply chain
+func brokenLinksTable(broken []api.BrokenLink, filters Filters) *Table {
+	table := &Table{
+		Title:   "Broken chains",
+		Icon:    "fa-chain-broken",
+		Filters: filters,
+		Headers: []string{"Kind", "Project", "Name", "Image", "Reason"},
+		Rows:    [][]Cell{},
+	}
+	for _, link := range broken {
+		if !filters.Matches(link.Namespace) {
+			continue
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Value: link.Kind},
+			{Value: link.Namespace},
+			{Value: link.Name, Link: consumerURL(link.ImageConsumer)},
+			{Value: link.Image},
+			{Value: link.Reason, Highlight: true},
+		})
+	}
+	return table
+}
+
+// consumerURL returns the URL of the page of the given image consumer, or an empty string if it has no page
+func consumerURL(consumer api.ImageConsumer) string {
+	switch consumer.Kind {
+	case "BuildConfig":
+		return fmt.Sprintf("/projects/%s/buildconfigs/%s", consumer.Namespace, consumer.Name)
+	case "DeploymentConfig":
+		return fmt.Sprintf("/projects/%s/deploymentconfigs/%s", consumer.Namespace, consumer.Name)
+	default:
+		return ""
+	}
+}
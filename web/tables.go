@@ -0,0 +1,849 @@
+package web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vbehar/openshift-dashboard/api"
+
+	buildapi "github.com/openshift/origin/pkg/build/api"
+	imageapi "github.com/openshift/origin/pkg/image/api"
+	routeapi "github.com/openshift/origin/pkg/route/api"
+
+	"github.com/julienschmidt/httprouter"
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+const (
+	// timeFormat is the format of the dates displayed in the tables
+	timeFormat = "2006-01-02 15:04:05"
+)
+
+// Cell is a value of a table.
+// In the HTML view, it can also be displayed as a link, as a status label, or as a sparkline of values,
+// be highlighted, and have a title (displayed as a tooltip).
+type Cell struct {
+	Value     string
+	Link      string
+	Label     bool
+	Highlight bool
+	Title     string
+
+	// Sparkline are the values of the sparkline, the negative ones being missing values
+	Sparkline []float64
+}
+
+// MarshalJSON writes only the value of the cell
+func (c Cell) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Value)
+}
+
+// Filters are the filters of the objects displayed in a table, read from the query parameters
+type Filters struct {
+	Project     string `json:"project,omitempty"`
+	Application string `json:"application,omitempty"`
+
+	// Selector is a label selector, such as "env in (prod,staging),!canary"
+	Selector string `json:"selector,omitempty"`
+
+	// Fields is a field selector, such as "status.phase=Running"
+	Fields string `json:"fields,omitempty"`
+
+	// GroupBy and Pivot are the names of the dimensions (such as "env") used to group the rows of the applications table,
+	// or to display a matrix of the applications and the values of the dimension
+	GroupBy string `json:"groupBy,omitempty"`
+	Pivot   string `json:"pivot,omitempty"`
+
+	// Image is the image of the supply chain, such as "openshift/origin-base" or "myproject/myapp:latest"
+	Image string `json:"image,omitempty"`
+}
+
+// filtersFor returns the filters of the given request
+func filtersFor(req *http.Request) Filters {
+	return Filters{
+		Project:     req.URL.Query().Get("project"),
+		Application: req.URL.Query().Get("application"),
+		Selector:    req.URL.Query().Get("selector"),
+		Fields:      req.URL.Query().Get("fields"),
+		GroupBy:     req.URL.Query().Get("groupBy"),
+		Pivot:       req.URL.Query().Get("pivot"),
+		Image:       req.URL.Query().Get("image"),
+	}
+}
+
+// Apply returns the objects of the given data that match the application filter and the selectors
+// (the project filter is applied by each table, on its own objects),
+// or an error if a selector or a dimension is invalid
+func (f Filters) Apply(data *api.Data) (*api.Data, error) {
+	selector, err := api.ParseObjectSelector(f.Selector, f.Fields)
+	if err != nil {
+		return nil, err
+	}
+	for _, dimension := range []string{f.GroupBy, f.Pivot} {
+		if _, found := data.Grouping.Dimension(dimension); len(dimension) > 0 && !found {
+			return nil, fmt.Errorf("Unknown dimension %v!", dimension)
+		}
+	}
+
+	if len(f.Application) > 0 {
+		appData := data.ForApplication(f.Application)
+		// the events have no labels: the tables filter them on their involved object
+		appData.Events = data.Events
+		appData.BuildIndex()
+		data = appData
+	}
+	return data.Select(selector), nil
+}
+
+// Matches returns true if the given namespace matches the project filter
+func (f Filters) Matches(namespace string) bool {
+	return len(f.Project) == 0 || f.Project == namespace
+}
+
+// IsEmpty returns true if there are no filters
+func (f Filters) IsEmpty() bool {
+	return len(f.Project) == 0 && len(f.Application) == 0 && len(f.Selector) == 0 && len(f.Fields) == 0 &&
+		len(f.GroupBy) == 0 && len(f.Pivot) == 0 && len(f.Image) == 0
+}
+
+// Query returns the query string of the filters, for the given format (or for the HTML view if empty)
+func (f Filters) Query(format string) string {
+	values := url.Values{}
+	if len(f.Project) > 0 {
+		values.Set("project", f.Project)
+	}
+	if len(f.Application) > 0 {
+		values.Set("application", f.Application)
+	}
+	if len(f.Selector) > 0 {
+		values.Set("selector", f.Selector)
+	}
+	if len(f.Fields) > 0 {
+		values.Set("fields", f.Fields)
+	}
+	if len(f.GroupBy) > 0 {
+		values.Set("groupBy", f.GroupBy)
+	}
+	if len(f.Pivot) > 0 {
+		values.Set("pivot", f.Pivot)
+	}
+	if len(f.Image) > 0 {
+		values.Set("image", f.Image)
+	}
+	if len(format) > 0 {
+		values.Set("format", format)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
+
+// Table is a table of objects, displayed in the HTML views or exported (in CSV or JSON).
+// Both use the same columns, defined by the table's builder.
+type Table struct {
+	Name    string   `json:"name"`
+	Title   string   `json:"title"`
+	Icon    string   `json:"-"`
+	Filters Filters  `json:"filters"`
+	Headers []string `json:"headers"`
+	Rows    [][]Cell `json:"rows"`
+}
+
+// ExportURL returns the URL to export the table (with its filters) in the given format
+func (t *Table) ExportURL(format string) string {
+	return "/" + t.Name + t.Filters.Query(format)
+}
+
+// WriteCSV writes the table in CSV format, with a header line
+func (t *Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(t.Headers); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		values := make([]string, len(row))
+		for i, cell := range row {
+			values[i] = csvSafe(cell.Value)
+		}
+		if err := writer.Write(values); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe escapes the given value with a leading quote if a spreadsheet would read it as a formula
+// (because it starts with "=", "+", "-", "@", a tab or a carriage return), unless it is a number.
+// The values come from the objects (labels, messages, hosts, ...), so they can't be trusted.
+func csvSafe(value string) string {
+	if len(value) == 0 || !strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
+
+// tableBuilder builds a table from the given data, with the given filters
+type tableBuilder func(data *api.Data, filters Filters) *Table
+
+// tables are the builders of the tables that can be displayed or exported, by name
+// (which is also the path of their view)
+var tables = map[string]tableBuilder{
+	"applications": applicationsTable,
+	"promotions":   promotionsTable,
+	"routes":       routesTable,
+	"services":     servicesTable,
+	"pods":         podsTable,
+	"builds":       buildsTable,
+	"images":       imagesTable,
+	"imagestreams": imageStreamsTable,
+	"events":       eventsTable,
+}
+
+// unfilteredTables are the tables built from all the objects, because their reports need the objects
+// that don't match the filters (such as the service of a route): they apply the filters to their rows
+var unfilteredTables = map[string]bool{
+	"routes":   true,
+	"services": true,
+}
+
+// TablePage is the data exposed to the "table" view
+type TablePage struct {
+	*Page
+	Table *Table
+
+	// Projects and Applications are the possible values of the filters
+	Projects     []string
+	Applications []string
+
+	// Dimensions are the dimensions that can be used to group or pivot the applications
+	Dimensions []api.Dimension
+}
+
+// TableHandler returns a handler that answers HTTP requests with the table of the given name,
+// filtered by the "project" and "application" query parameters.
+// The "format" query parameter can be used to export the table in "csv" or "json", instead of the "table" view.
+func (c *Context) TableHandler(name string) httprouter.Handle {
+	build := tables[name]
+	return func(w http.ResponseWriter, req *http.Request, _ httprouter.Params) {
+		snapshot, err := c.ClientWrapperFor(req).Snapshot()
+		if err != nil {
+			fmt.Fprintf(w, "failed to load data: %v", err)
+			return
+		}
+		d := snapshot.Data
+
+		filters := filtersFor(req)
+		filtered, err := filters.Apply(d)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		source := filtered
+		if unfilteredTables[name] {
+			source = d
+		}
+		table := build(source, filters)
+
+		switch format := req.URL.Query().Get("format"); format {
+		case "":
+			data := &TablePage{
+				Page:  c.NewPage(w, req).withSnapshot(snapshot),
+				Table: table,
+			}
+			for _, project := range d.Projects {
+				data.Projects = append(data.Projects, project.Name)
+			}
+			sort.Strings(data.Projects)
+			for _, app := range d.Applications {
+				data.Applications = append(data.Applications, app.Name())
+			}
+			data.Dimensions = d.Grouping.Dimensions
+			c.Render.HTML(w, http.StatusOK, "table", data)
+		default:
+			c.exportTable(w, table, format)
+		}
+	}
+}
+
+// exportTable writes the given table in the given format: "csv" or "json"
+func (c *Context) exportTable(w http.ResponseWriter, table *Table, format string) {
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table.Name+".csv"))
+		if err := table.WriteCSV(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	case "json":
+		c.Render.JSON(w, http.StatusOK, table)
+	default:
+		http.Error(w, fmt.Sprintf("Unknown format %v: it should be csv or json!", format), http.StatusBadRequest)
+	}
+}
+
+// applicationsTable builds the table of the applications, with their dimensions and the number of their objects.
+// With the groupBy filter, there is a row per value of the dimension and application.
+// With the pivot filter, it is a matrix of the health of the applications, per value of the dimension.
+func applicationsTable(data *api.Data, filters Filters) *Table {
+	table := &Table{
+		Name:    "applications",
+		Title:   "Applications",
+		Icon:    "fa-list-alt",
+		Filters: filters,
+		Headers: []string{"Application"},
+		Rows:    [][]Cell{},
+	}
+	counts := []string{"BuildConfigs", "DeploymentConfigs", "ImageStreams", "Services", "Routes"}
+
+	if pivot, found := data.Grouping.Dimension(filters.Pivot); found {
+		values := data.DimensionValues(pivot)
+		table.Title = "Applications by " + pivot.DisplayTitle()
+		table.Headers = append(table.Headers, values...)
+		for _, app := range data.Applications {
+			appData := data.ForApplication(app.Name())
+			if !filters.matchesApplication(appData) {
+				continue
+			}
+			row := []Cell{{Value: app.Name(), Link: "/applications/" + app.Name()}}
+			for _, value := range values {
+				valueData := appData.ForDimensionValue(pivot, value)
+				if len(valueData.Applications) == 0 {
+					row = append(row, Cell{})
+					continue
+				}
+				row = append(row, Cell{Value: string(valueData.ApplicationHealthOf(app.Name(), "")), Label: true})
+			}
+			table.Rows = append(table.Rows, row)
+		}
+		return table
+	}
+
+	if groupBy, found := data.Grouping.Dimension(filters.GroupBy); found {
+		table.Title = "Applications by " + groupBy.DisplayTitle()
+		table.Headers = append([]string{groupBy.DisplayTitle()}, table.Headers...)
+		table.Headers = append(table.Headers, counts...)
+		for _, value := range append(data.DimensionValues(groupBy), "") {
+			valueData := data.ForDimensionValue(groupBy, value)
+			for _, app := range valueData.Applications {
+				appData := valueData.ForApplication(app.Name())
+				if !filters.matchesApplication(appData) {
+					continue
+				}
+				group := Cell{Value: value}
+				if len(value) == 0 {
+					group.Value = "(none)"
+				}
+				row := []Cell{group, {Value: app.Name(), Link: "/applications/" + app.Name()}}
+				table.Rows = append(table.Rows, append(row, applicationCounts(appData)...))
+			}
+		}
+		return table
+	}
+
+	for _, dimension := range data.Grouping.Dimensions {
+		table.Headers = append(table.Headers, dimension.DisplayTitle())
+	}
+	table.Headers = append(table.Headers, counts...)
+	for _, app := range data.Applications {
+		appData := data.ForApplication(app.Name())
+		if !filters.matchesApplication(appData) {
+			continue
+		}
+		row := []Cell{{Value: app.Name(), Link: "/applications/" + app.Name()}}
+		for _, dimension := range data.Grouping.Dimensions {
+			row = append(row, Cell{Value: strings.Join(appData.DimensionValues(dimension), ", ")})
+		}
+		table.Rows = append(table.Rows, append(row, applicationCounts(appData)...))
+	}
+	return table
+}
+
+// matchesApplication returns true if the given application data are in the project of the filter
+// (or if they are not in any project)
+func (f Filters) matchesApplication(appData *api.Data) bool {
+	if len(f.Project) == 0 || len(appData.Projects) == 0 {
+		return true
+	}
+	for _, project := range appData.Projects {
+		if project.Name == f.Project {
+			return true
+		}
+	}
+	return false
+}
+
+// applicationCounts returns the cells with the number of objects of the given application data,
+// for the columns of the applications table
+func applicationCounts(appData *api.Data) []Cell {
+	return []Cell{
+		{Value: strconv.Itoa(len(appData.BuildConfigs))},
+		{Value: strconv.Itoa(len(appData.DeploymentConfigs))},
+		{Value: strconv.Itoa(len(appData.ImageStreams))},
+		{Value: strconv.Itoa(len(appData.Services))},
+		{Value: strconv.Itoa(len(appData.Routes))},
+	}
+}
+
+// promotionsTable builds the matrix of the images deployed by the components (DeploymentConfigs) of the applications
+// in each environment, with the builds that produced them, and the drifts between the environments
+func promotionsTable(data *api.Data, filters Filters) *Table {
+	matrix := data.PromotionMatrix()
+	table := &Table{
+		Name:    "promotions",
+		Title:   "Promotions",
+		Icon:    "fa-level-up",
+		Filters: filters,
+		Headers: append(append([]string{"Application", "Component"}, matrix.Environments...), "Drift"),
+		Rows:    [][]Cell{},
+	}
+	for _, promotion := range matrix.Promotions {
+		if len(filters.Application) > 0 && promotion.Application != filters.Application {
+			continue
+		}
+		inProject := len(filters.Project) == 0
+		for _, image := range promotion.Images {
+			inProject = inProject || filters.Matches(image.Namespace)
+		}
+		if !inProject {
+			continue
+		}
+
+		row := []Cell{{Value: promotion.Application, Link: "/applications/" + promotion.Application}, {Value: promotion.Name}}
+		drifts := []string{}
+		for _, environment := range matrix.Environments {
+			image, found := promotion.Images[environment]
+			if !found {
+				row = append(row, Cell{})
+				continue
+			}
+			row = append(row, deployedImageCell(image))
+			if len(image.Drift) > 0 {
+				drifts = append(drifts, environment+": "+image.Drift)
+			}
+		}
+		table.Rows = append(table.Rows, append(row, Cell{Value: strings.Join(drifts, ", "), Highlight: len(drifts) > 0}))
+	}
+	return table
+}
+
+// deployedImageCell returns the cell of an image deployed in an environment:
+// the build that produced it and its commit (or its digest if the build is unknown), linked to the build
+func deployedImageCell(image *api.DeployedImage) Cell {
+	cell := Cell{
+		Value:     image.Image,
+		Highlight: len(image.Drift) > 0,
+		Title:     strings.Join(append([]string{image.Namespace + "/" + image.DeploymentConfig, image.Image}, image.Tags...), "\n"),
+	}
+	if len(image.Digest) > 0 {
+		cell.Value = image.ShortDigest()
+	}
+	if build := image.Build; build != nil {
+		cell.Value = build.Name
+		if commit := image.Commit(); len(commit) > 0 {
+			cell.Value += " (" + shortCommit(commit) + ")"
+		}
+		cell.Link = "/projects/" + build.Namespace + "/builds/" + build.Name
+	}
+	return cell
+}
+
+// shortCommit returns the abbreviated hash of the given commit
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// routesTable builds the table of the routes, with their TLS termination and certificate, and their misconfigurations.
+// It is built from all the objects, and applies the filters to its rows.
+func routesTable(data *api.Data, filters Filters) *Table {
+	table := &Table{
+		Name:    "routes",
+		Title:   "Routes",
+		Icon:    "fa-globe",
+		Filters: filters,
+		Headers: []string{"Project", "Route", "Application", "Host", "Path", "Service", "TLS Termination",
+			"Certificate", "SANs", "Expires", "Problems"},
+		Rows: [][]Cell{},
+	}
+	// the reports need all the routes (for the conflicts) and services, so they are filtered afterwards
+	selected := make(map[string]bool)
+	if filtered, err := filters.Apply(data); err == nil {
+		for _, route := range filtered.Routes {
+			selected[route.Namespace+"/"+route.Name] = true
+		}
+	}
+	for _, report := range data.RouteReports(time.Now()) {
+		route := report.Route
+		if !filters.Matches(route.Namespace) || !selected[route.Namespace+"/"+route.Name] {
+			continue
+		}
+		row := []Cell{
+			{Value: route.Namespace},
+			{Value: route.Name},
+			applicationCell(data.ApplicationOf(route.Labels)),
+			{Value: route.Host, Link: routeURL(route)},
+			{Value: route.Path},
+			{Value: route.ServiceName},
+			{Value: tlsTerminationOf(route)},
+			{},
+			{},
+			{},
+			{Value: strings.Join(report.Problems, ", "), Highlight: len(report.Problems) > 0},
+		}
+		if cert := report.Certificate; cert != nil {
+			row[7] = Cell{Value: cert.Subject, Title: "Issued by " + cert.Issuer}
+			row[8] = Cell{Value: strings.Join(cert.DNSNames, ", ")}
+			row[9] = Cell{Value: cert.NotAfter.Format(timeFormat), Highlight: cert.ExpiresSoon}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// servicesTable builds the table of the services, with the pods they select and their ready endpoints,
+// and their misconfigurations.
+// It is built from all the objects, and applies the filters to its rows.
+func servicesTable(data *api.Data, filters Filters) *Table {
+	table := &Table{
+		Name:    "services",
+		Title:   "Services",
+		Icon:    "fa-exchange",
+		Filters: filters,
+		Headers: []string{"Project", "Service", "Application", "Selector", "Ports", "Ready pods", "Endpoints",
+			"Pods applications", "Problems"},
+		Rows: [][]Cell{},
+	}
+	// the reports need all the pods and endpoints, so they are filtered afterwards
+	// (the application filter also matches the services of the pods of the application)
+	selected := make(map[string]bool)
+	if filtered, err := (Filters{Selector: filters.Selector, Fields: filters.Fields}).Apply(data); err == nil {
+		for _, service := range filtered.Services {
+			selected[service.Namespace+"/"+service.Name] = true
+		}
+	}
+	for _, report := range data.ServiceReports() {
+		service := report.Service
+		if !filters.Matches(service.Namespace) || !selected[service.Namespace+"/"+service.Name] {
+			continue
+		}
+		application := data.ApplicationOf(service.Labels)
+		if len(filters.Application) > 0 && application != filters.Application && !contains(report.Applications, filters.Application) {
+			continue
+		}
+		ports := []string{}
+		for _, port := range service.Spec.Ports {
+			ports = append(ports, fmt.Sprintf("%d->%v/%v", port.Port, port.TargetPort.String(), port.Protocol))
+		}
+		endpoints := Cell{Value: "unknown"}
+		if report.Endpoints >= 0 {
+			endpoints = Cell{Value: strconv.Itoa(report.Endpoints), Highlight: report.Endpoints == 0}
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Value: service.Namespace},
+			{Value: service.Name},
+			applicationCell(application),
+			{Value: labels.SelectorFromSet(service.Spec.Selector).String()},
+			{Value: strings.Join(ports, ", ")},
+			{Value: fmt.Sprintf("%d/%d", report.ReadyPods, report.Pods), Highlight: report.ReadyPods < report.Pods},
+			endpoints,
+			{Value: strings.Join(report.Applications, ", "), Highlight: len(report.Applications) > 1},
+			{Value: strings.Join(report.Problems, ", "), Highlight: len(report.Problems) > 0},
+		})
+	}
+	return table
+}
+
+// contains returns true if the given value is in the given slice
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// podsTable builds the table of the pods, with their status
+func podsTable(data *api.Data, filters Filters) *Table {
+	table := &Table{
+		Name:    "pods",
+		Title:   "Pods",
+		Icon:    "fa-cubes",
+		Filters: filters,
+		Headers: []string{"Project", "Pod", "Application", "Status", "Ready", "Restarts", "Node", "Created"},
+		Rows:    [][]Cell{},
+	}
+	for _, pod := range data.Pods {
+		if !filters.Matches(pod.Namespace) {
+			continue
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Value: pod.Namespace},
+			{Value: pod.Name},
+			applicationCell(data.ApplicationOf(pod.Labels)),
+			{Value: string(pod.Status.Phase), Label: true},
+			{Value: strconv.FormatBool(api.IsPodReady(pod))},
+			{Value: strconv.Itoa(api.RestartCountOf(pod))},
+			{Value: pod.Spec.NodeName},
+			{Value: pod.CreationTimestamp.Format(timeFormat)},
+		})
+	}
+	return table
+}
+
+// buildsTable builds the table of the builds, from the most recent one
+func buildsTable(data *api.Data, filters Filters) *Table {
+	table := &Table{
+		Name:    "builds",
+		Title:   "Builds",
+		Icon:    "fa-gear",
+		Filters: filters,
+		Headers: []string{"Project", "Build", "BuildConfig", "Status", "Created", "Duration"},
+		Rows:    [][]Cell{},
+	}
+	builds := make([]buildapi.Build, len(data.Builds))
+	copy(builds, data.Builds)
+	sort.Sort(sort.Reverse(api.BuildsByCreationTimestamp(builds)))
+	for _, build := range builds {
+		if !filters.Matches(build.Namespace) {
+			continue
+		}
+		duration := ""
+		if build.Status.StartTimestamp != nil && build.Status.CompletionTimestamp != nil {
+			duration = build.Status.CompletionTimestamp.Sub(build.Status.StartTimestamp.Time).String()
+		}
+		bc := api.BuildConfigNameOf(build)
+		bcCell := Cell{Value: bc}
+		if len(bc) > 0 {
+			bcCell.Link = "/projects/" + build.Namespace + "/buildconfigs/" + bc
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Value: build.Namespace},
+			{Value: build.Name, Link: "/projects/" + build.Namespace + "/builds/" + build.Name},
+			bcCell,
+			{Value: string(build.Status.Phase), Label: true},
+			{Value: build.CreationTimestamp.Format(timeFormat)},
+			{Value: duration},
+		})
+	}
+	return table
+}
+
+// imagesTable builds the inventory of the images used by the containers of the pods and DeploymentConfigs,
+// with where they come from, who uses them, and whether they are stale
+func imagesTable(data *api.Data, filters Filters) *Table {
+	table := &Table{
+		Name:    "images",
+		Title:   "Images",
+		Icon:    "fa-archive",
+		Filters: filters,
+		Headers: []string{"Image", "Registry", "Repository", "Tag", "Digest", "ImageStream", "Projects", "Applications",
+			"Pods", "DeploymentConfigs", "Created", "Newer tag", "Warnings"},
+		Rows: [][]Cell{},
+	}
+	for _, image := range data.ContainerImages() {
+		inProject := len(filters.Project) == 0
+		for _, project := range image.Projects {
+			inProject = inProject || filters.Matches(project)
+		}
+		if !inProject {
+			continue
+		}
+
+		imageStream := image.ImageStream
+		if len(image.ImageStreamTag) > 0 {
+			imageStream += ":" + image.ImageStreamTag
+		}
+		created := ""
+		if !image.Created.IsZero() {
+			created = image.Created.Format(timeFormat)
+		}
+		warnings := []string{}
+		if image.Latest {
+			warnings = append(warnings, "latest tag")
+		}
+		if image.External {
+			warnings = append(warnings, "external registry")
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Value: image.Image},
+			{Value: image.Registry},
+			{Value: image.Repository},
+			{Value: image.Tag},
+			{Value: image.ShortDigest(), Title: image.Digest},
+			{Value: imageStream},
+			{Value: strings.Join(image.Projects, ", ")},
+			{Value: strings.Join(image.Applications, ", ")},
+			{Value: strconv.Itoa(image.Pods)},
+			{Value: strconv.Itoa(len(image.DeploymentConfigs)), Title: strings.Join(image.DeploymentConfigs, "\n")},
+			{Value: created},
+			{Value: image.NewerTag, Highlight: len(image.NewerTag) > 0},
+			{Value: strings.Join(warnings, ", "), Highlight: len(warnings) > 0},
+		})
+	}
+	return table
+}
+
+// imageStreamsTable builds the table of the ImageStreams, one row per tag
+func imageStreamsTable(data *api.Data, filters Filters) *Table {
+	table := &Table{
+		Name:    "imagestreams",
+		Title:   "ImageStreams",
+		Icon:    "fa-database",
+		Filters: filters,
+		Headers: []string{"Project", "ImageStream", "Application", "Tag", "Image", "Updated"},
+		Rows:    [][]Cell{},
+	}
+	for _, is := range data.ImageStreams {
+		if !filters.Matches(is.Namespace) {
+			continue
+		}
+		for _, tag := range sortedTags(is) {
+			row := []Cell{
+				{Value: is.Namespace},
+				{Value: is.Name},
+				applicationCell(data.ApplicationOf(is.Labels)),
+				{Value: tag},
+				{},
+				{},
+			}
+			if events := is.Status.Tags[tag].Items; len(events) > 0 {
+				row[4].Value = events[0].DockerImageReference
+				row[5].Value = events[0].Created.Format(timeFormat)
+			}
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table
+}
+
+// eventsTable builds the table of the events, from the most recent one
+func eventsTable(data *api.Data, filters Filters) *Table {
+	table := &Table{
+		Name:    "events",
+		Title:   "Events",
+		Icon:    "fa-bolt",
+		Filters: filters,
+		Headers: []string{"Project", "Object", "Reason", "Message", "Count", "First Seen", "Last Seen"},
+		Rows:    [][]Cell{},
+	}
+
+	var objects map[string]bool
+	if len(filters.Application) > 0 {
+		objects = applicationObjects(data)
+	}
+
+	events := make([]kapi.Event, len(data.Events))
+	copy(events, data.Events)
+	sort.Sort(eventsByLastTimestamp(events))
+	for _, event := range events {
+		if !filters.Matches(event.Namespace) {
+			continue
+		}
+		object := event.InvolvedObject
+		if objects != nil && !objects[object.Kind+"/"+object.Namespace+"/"+object.Name] {
+			continue
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Value: event.Namespace},
+			{Value: object.Kind + " " + object.Name},
+			{Value: event.Reason},
+			{Value: event.Message},
+			{Value: strconv.Itoa(event.Count)},
+			{Value: event.FirstTimestamp.Format(timeFormat)},
+			{Value: event.LastTimestamp.Format(timeFormat)},
+		})
+	}
+	return table
+}
+
+// applicationCell returns the cell of the given application of an object (which can be empty)
+func applicationCell(application string) Cell {
+	if len(application) == 0 {
+		return Cell{}
+	}
+	return Cell{Value: application, Link: "/applications/" + application}
+}
+
+// routeURL returns the URL exposed by the given route
+func routeURL(route routeapi.Route) string {
+	scheme := "http"
+	if route.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + route.Host + route.Path
+}
+
+// tlsTerminationOf returns the TLS termination of the given route, or "none"
+func tlsTerminationOf(route routeapi.Route) string {
+	if route.TLS == nil || len(route.TLS.Termination) == 0 {
+		return "none"
+	}
+	return string(route.TLS.Termination)
+}
+
+// sortedTags returns the tags of the given ImageStream, sorted by name
+func sortedTags(is imageapi.ImageStream) []string {
+	tags := []string{}
+	for tag := range is.Status.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// applicationObjects returns the keys ("Kind/namespace/name") of the objects of the given (application) data,
+// that can be the involved object of an event
+func applicationObjects(data *api.Data) map[string]bool {
+	objects := make(map[string]bool)
+	add := func(kind string, meta kapi.ObjectMeta) {
+		objects[kind+"/"+meta.Namespace+"/"+meta.Name] = true
+	}
+	for _, pod := range data.Pods {
+		add("Pod", pod.ObjectMeta)
+	}
+	for _, rc := range data.ReplicationControllers {
+		add("ReplicationController", rc.ObjectMeta)
+	}
+	for _, dc := range data.DeploymentConfigs {
+		add("DeploymentConfig", dc.ObjectMeta)
+	}
+	for _, bc := range data.BuildConfigs {
+		add("BuildConfig", bc.ObjectMeta)
+	}
+	for _, build := range data.Builds {
+		add("Build", build.ObjectMeta)
+	}
+	for _, service := range data.Services {
+		add("Service", service.ObjectMeta)
+	}
+	for _, route := range data.Routes {
+		add("Route", route.ObjectMeta)
+	}
+	return objects
+}
+
+// eventsByLastTimestamp sorts the events from the most recent one
+type eventsByLastTimestamp []kapi.Event
+
+func (e eventsByLastTimestamp) Len() int      { return len(e) }
+func (e eventsByLastTimestamp) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e eventsByLastTimestamp) Less(i, j int) bool {
+	return e[i].LastTimestamp.Time.After(e[j].LastTimestamp.Time)
+}
@@ -2,45 +2,84 @@
 package web
 
 import (
+	"fmt"
 	"log"
-	"net/http"
-	"os"
 	"time"
 
+	"github.com/vbehar/openshift-dashboard/config"
+
 	"github.com/codegangsta/negroni"
 	"github.com/julienschmidt/httprouter"
 	"github.com/tylerb/graceful"
 )
 
-// RunHttpServer runs an HTTP server on a port defined by the PORT env var (default to 8080)
-func RunHttpServer() {
-	port := Getenv("PORT", "8080")
-	publicDir := Getenv("PUBLIC_DIR", "public")
-
-	c := NewContext()
+// RunHttpServer runs an HTTP server on the configured port (default to 8080),
+// with the given configuration, read from the given file (which is reloaded on SIGHUP)
+func RunHttpServer(conf *config.Config, configFile string) {
+	c := NewContext(conf, configFile)
 
 	router := httprouter.New()
 	router.GET("/", c.HomeHandler)
 	router.GET("/stats", c.StatsHandler)
+	router.GET("/applications/:name", c.ApplicationHandler)
+	router.GET("/projects/:namespace/buildconfigs/:name", c.BuildConfigHandler)
+	router.GET("/projects/:namespace/builds/:name", c.BuildHandler)
+	router.GET("/projects/:namespace/deploymentconfigs/:name", c.DeploymentConfigHandler)
+	router.GET("/dashboards/:name", c.DashboardHandler)
+	router.GET("/supplychain", c.SupplyChainHandler)
+	router.GET("/cleanup", c.CleanupHandler)
+	for name := range tables {
+		router.GET("/"+name, c.TableHandler(name))
+	}
+
+	if c.Alerts != nil {
+		router.GET("/alerts", c.AlertsHandler)
+	}
+
+	if c.History != nil {
+		router.GET("/trends", c.TrendsHandler)
+	}
+
+	if c.Prober != nil {
+		router.GET("/availability", c.AvailabilityHandler)
+	}
+
+	// the actions are performed with the credentials of the authenticated users only
+	if c.ActionsEnabled && c.Authenticator != nil {
+		router.GET("/audit", c.AuditHandler)
+		router.POST("/projects/:namespace/buildconfigs/:name/instantiate", c.StartBuildHandler)
+		router.POST("/projects/:namespace/builds/:name/clone", c.RerunBuildHandler)
+		router.POST("/projects/:namespace/builds/:name/cancel", c.CancelBuildHandler)
+		router.POST("/projects/:namespace/deploymentconfigs/:name/scale", c.ScaleDeploymentConfigHandler)
+		router.POST("/projects/:namespace/deploymentconfigs/:name/redeploy", c.RedeployDeploymentConfigHandler)
+		router.POST("/projects/:namespace/deploymentconfigs/:name/rollback", c.RollbackDeploymentConfigHandler)
+	}
 
 	n := negroni.New(
 		negroni.NewRecovery(),
 		negroni.NewLogger(),
-		negroni.NewStatic(http.Dir(publicDir)),
+		c.Assets,
 		c.Stats,
 	)
 
+	if c.Authenticator != nil {
+		c.Authenticator.RegisterHandlers(router)
+		n.Use(c.Authenticator)
+	}
+
 	n.UseHandler(router)
 
-	log.Printf("Starting openshift-dashboard on port %v\n", port)
-	graceful.Run(":"+port, 10*time.Second, n)
-}
+	// without authentication, the pages are displayed with the data refreshed in the background
+	if c.Refresher.HasListeners() || c.Authenticator == nil {
+		go c.Refresher.Run(nil)
+	}
 
-// Getenv returns the value of the env var with the given name,
-// or fallback to the given default value.
-func Getenv(envVarName string, defaultValue string) string {
-	if value := os.Getenv(envVarName); len(value) != 0 {
-		return value
+	if c.Prober != nil {
+		go c.Prober.Run(nil)
 	}
-	return defaultValue
+
+	go c.ReloadConfigOnSignal()
+
+	log.Printf("Starting openshift-dashboard on port %v\n", conf.Server.Port)
+	graceful.Run(fmt.Sprintf(":%d", conf.Server.Port), 10*time.Second, n)
 }